@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Read absence days from file
+// Line format: <date> [- <date>] <type> [comment]
+// Type is one of: vacation, sick, holiday, flex, unpaid
+func ReadAbsencesFile(path *string, layout *string) (absences DayTypeDateMap, err error) {
+	f, err := os.Open(*path)
+
+	if err != nil {
+		fmt.Printf("ERROR: Failed to open absences file (path: %s) Err: %s\n", *path, err.Error())
+		return nil, err
+	}
+	defer f.Close()
+
+	absences = DayTypeDateMap{}
+
+	// Date layout may consist of multiple words
+	dateFields := len(strings.Fields(*layout))
+
+	parseDate := func(line Line, fields []string) (time.Time, error) {
+		if len(fields) < dateFields {
+			fmt.Printf("ERROR: Absences file: %s Line: %v: Date missing.\n", *path, line)
+			return time.Time{}, errors.New("invalid line in absences file")
+		}
+		date, err := time.Parse(*layout, strings.Join(fields[:dateFields], " "))
+		if err != nil {
+			fmt.Printf("ERROR: Absences file: %s Line: %v: Could not parse date. Err: %s\n", *path, line, err.Error())
+			return time.Time{}, err
+		}
+		return DateKey(date), nil
+	}
+
+	scanner := bufio.NewScanner(f)
+
+	var line Line = 0
+	for scanner.Scan() {
+		line++
+
+		rawstr := strings.TrimSpace(scanner.Text())
+
+		// Skip empty, skip commented out
+		if rawstr == "" || rawstr[0] == '#' {
+			continue
+		}
+
+		fields := strings.Fields(rawstr)
+
+		start, err := parseDate(line, fields)
+		if err != nil {
+			return nil, err
+		}
+		fields = fields[dateFields:]
+
+		// Optional range end
+		end := start
+		if len(fields) > 0 && fields[0] == "-" {
+			end, err = parseDate(line, fields[1:])
+			if err != nil {
+				return nil, err
+			}
+			fields = fields[1+dateFields:]
+		}
+		if end.Before(start) {
+			fmt.Printf("ERROR: Absences file: %s Line: %v: Range end before start. Value: '%s'\n", *path, line, rawstr)
+			return nil, errors.New("range end before start")
+		}
+
+		if len(fields) <= 0 {
+			fmt.Printf("ERROR: Absences file: %s Line: %v: Day type missing. Value: '%s'\n", *path, line, rawstr)
+			return nil, errors.New("day type missing")
+		}
+		dtype, ok := DayTypeMapping[strings.ToLower(fields[0])]
+		if !ok {
+			fmt.Printf("ERROR: Absences file: %s Line: %v: Unknown day type: '%s'\n", *path, line, fields[0])
+			return nil, errors.New("unknown day type")
+		}
+
+		for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+			if _, ok := absences[d]; ok {
+				fmt.Printf("WARNING: Absences file: %s Line: %v: Date %s already defined, overriding.\n", *path, line, d.Format(*layout))
+			}
+			absences[d] = dtype
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		fmt.Println("ERROR: Failed to read line from absences file. Err:", err.Error())
+		return nil, err
+	}
+
+	return absences, nil
+}
+
+// Day type for a date from absences file and holiday calendar
+// Type derived from import entries is kept unless absences file overrides it
+func LookupDayType(config *Config, date time.Time, dtype DayType) DayType {
+	if t, ok := config.Absences[DateKey(date)]; ok {
+		return t
+	}
+	if dtype == WORK_DAY && config.Holidays != nil && config.Holidays.Lookup(date) != nil {
+		return HOLIDAY_DAY
+	}
+	return dtype
+}
+
+// Assign day types for imported days
+func ApplyDayTypes(config *Config, entries *ListSingleEntry) {
+	for _, e := range *entries {
+		e.dtype = LookupDayType(config, e.date, e.dtype)
+	}
+}
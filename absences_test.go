@@ -0,0 +1,95 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReadAbsencesFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		layout   string
+		content  string
+		absences map[string]DayType
+		wantErr  bool
+	}{
+		{
+			name:    "single days and ranges",
+			layout:  "02.01.2006",
+			content: "# comment\n02.10.2023 - 04.10.2023 vacation Autumn trip\n06.10.2023 SICK\n09.10.2023 flex\n",
+			absences: map[string]DayType{
+				"2023-10-02": VACATION_DAY,
+				"2023-10-03": VACATION_DAY,
+				"2023-10-04": VACATION_DAY,
+				"2023-10-06": SICK_DAY,
+				"2023-10-09": FLEX_OFF_DAY,
+			},
+		},
+		{
+			name:    "later line overrides",
+			layout:  "02.01.2006",
+			content: "02.10.2023 - 03.10.2023 vacation\n03.10.2023 unpaid\n",
+			absences: map[string]DayType{
+				"2023-10-02": VACATION_DAY,
+				"2023-10-03": UNPAID_DAY,
+			},
+		},
+		{
+			name:    "layout of multiple words",
+			layout:  "Jan 2 2006",
+			content: "Oct 2 2023 - Oct 3 2023 holiday\n",
+			absences: map[string]DayType{
+				"2023-10-02": HOLIDAY_DAY,
+				"2023-10-03": HOLIDAY_DAY,
+			},
+		},
+		{
+			name:    "unknown day type",
+			layout:  "02.01.2006",
+			content: "02.10.2023 party\n",
+			wantErr: true,
+		},
+		{
+			name:    "day type missing",
+			layout:  "02.01.2006",
+			content: "02.10.2023\n",
+			wantErr: true,
+		},
+		{
+			name:    "range end before start",
+			layout:  "02.01.2006",
+			content: "04.10.2023 - 02.10.2023 vacation\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "absences.txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			absences, err := ReadAbsencesFile(&path, &tt.layout)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(absences) != len(tt.absences) {
+				t.Fatalf("got %v days, want %v", len(absences), len(tt.absences))
+			}
+			for d, want := range tt.absences {
+				date, _ := time.Parse(time.DateOnly, d)
+				if got, ok := absences[date]; !ok || got != want {
+					t.Errorf("%s: got %v, want %v", d, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Combine time records of exports into single days
+// Days missing between records and until the end of the last week are added as zero hours
+// Excluded tasks count as zero, absence tasks mark the day type and are not counted as work
+func AggregateDays(config *Config, entries *ListTimeEntry) (arr *ListSingleEntry, err error) {
+	// Ensure something to process
+	if len(*entries) <= 0 {
+		println("WARNING: Nothing to process in import file. Double check input file correct.")
+		return nil, errors.New("nothing to process")
+	}
+
+	// Exports may list records in any order, earliest first
+	// Stable to keep the file order within a day
+	slices.SortStableFunc(*entries, func(a, b *TimeEntry) int { return a.date.Compare(b.date) })
+
+	// All Days, most probably max 365 or 2*365
+	arr = AsPtr(make(ListSingleEntry, 0, 1024))
+
+	// Skip excluded weekdays, no use
+	addMissing := func(date time.Time) {
+		if config.ExcludedWeekdays == nil || !ValueInArray(AsPtr(date.Weekday()), config.ExcludedWeekdays) {
+			*arr = append(*arr, &SingleEntry{date: date, duration: 0})
+		}
+	}
+
+	// Day entry, combination of entries from the same day
+	var day *SingleEntry
+
+	for _, entry := range *entries {
+		if day != nil && !day.date.Equal(entry.date) {
+			*arr = append(*arr, day)
+
+			// If a day was skipped, we have to mark it as zero hours done
+			// If its included as a workday
+			for missingDate := day.date.AddDate(0, 0, 1); entry.date.After(missingDate.Add(time.Hour)); missingDate = missingDate.AddDate(0, 0, 1) {
+				addMissing(missingDate)
+			}
+			day = nil
+		}
+		if day == nil {
+			day = &SingleEntry{date: entry.date}
+		}
+
+		// Absence entries mark the day type, their hours are not work
+		var absence *DayType
+		for _, e := range entry.labels {
+			if dtype, ok := config.TaskDayTypes[*e]; ok {
+				absence = &dtype
+			}
+		}
+		// If current task is excluded, count entry as zero
+		excluded := false
+		for _, e := range entry.tasks {
+			if config.ExcludedTasks != nil && ValueInArray(e, config.ExcludedTasks) {
+				excluded = true
+			}
+		}
+
+		// Dont add balance if day excluded
+		if absence != nil {
+			if day.dtype != WORK_DAY && day.dtype != *absence {
+				fmt.Printf("WARNING: Row: %v: Multiple absence types for the same day, using: %s\n", entry.line, *DayTypeRevMapping[*absence])
+			}
+			day.dtype = *absence
+		} else if !excluded {
+			day.duration += entry.duration
+		}
+	}
+
+	// Add the last day and the potential future missing days for its week
+	// If the last workday is eg in the middle of the week
+	*arr = append(*arr, day)
+	_, wk := day.date.ISOWeek()
+	for missingDate := day.date.AddDate(0, 0, 1); ; missingDate = missingDate.AddDate(0, 0, 1) {
+		if _, wk2 := missingDate.ISOWeek(); wk2 != wk {
+			break
+		}
+		addMissing(missingDate)
+	}
+
+	return arr, nil
+}
+
+// Split a time record at midnight if it continues to next day(s)
+// Returns the start date and duration of each part, at least one part
+func SplitAtMidnight(start time.Time, d time.Duration) (dates []time.Time, durations []time.Duration) {
+	if d <= 0 {
+		return []time.Time{time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)}, []time.Duration{0}
+	}
+	for d > 0 {
+		date := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+		next := time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, start.Location())
+		part := min(d, next.Sub(start))
+		dates = append(dates, date)
+		durations = append(durations, part)
+		d -= part
+		start = next
+	}
+	return
+}
+
+// Time records of a work session, split at midnight
+// Breaks are subtracted from the first day of the session first
+func SessionTimeEntries(start time.Time, end time.Time, breaks time.Duration, line Line) (entries ListTimeEntry) {
+	dates, durations := SplitAtMidnight(start, end.Sub(start))
+	for i := range dates {
+		cut := min(breaks, durations[i])
+		breaks -= cut
+		entries = append(entries, &TimeEntry{date: dates[i], duration: HoursFromDuration(durations[i] - cut), line: line})
+	}
+	return entries
+}
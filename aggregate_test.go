@@ -0,0 +1,125 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAggregateDays(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse("02.01.2006", s)
+		return d
+	}
+	entry := func(s string, duration Hours, task string) *TimeEntry {
+		return &TimeEntry{date: date(s), duration: duration, tasks: ListString{AsPtr(task)}, labels: ListString{AsPtr(task)}}
+	}
+
+	tests := []struct {
+		name     string
+		excluded *ListWeekday
+		entries  ListTimeEntry
+		days     []SingleEntry
+	}{
+		{
+			name:     "gaps filled on workdays until end of week",
+			excluded: &ListWeekday{AsPtr(time.Saturday), AsPtr(time.Sunday)},
+			entries: ListTimeEntry{
+				entry("06.10.2023", 400, "development"),
+				entry("04.10.2023", 725, "development"),
+				entry("06.10.2023", 300, "development"),
+			},
+			days: []SingleEntry{
+				{date: date("04.10.2023"), duration: 725},
+				{date: date("05.10.2023")},
+				{date: date("06.10.2023"), duration: 700},
+			},
+		},
+		{
+			name: "gaps filled without excluded weekdays",
+			entries: ListTimeEntry{
+				entry("06.10.2023", 725, "development"),
+				entry("09.10.2023", 725, "development"),
+			},
+			days: []SingleEntry{
+				{date: date("06.10.2023"), duration: 725},
+				{date: date("07.10.2023")},
+				{date: date("08.10.2023")},
+				{date: date("09.10.2023"), duration: 725},
+				{date: date("10.10.2023")},
+				{date: date("11.10.2023")},
+				{date: date("12.10.2023")},
+				{date: date("13.10.2023")},
+				{date: date("14.10.2023")},
+				{date: date("15.10.2023")},
+			},
+		},
+		{
+			name:     "excluded task and absence",
+			excluded: &ListWeekday{AsPtr(time.Saturday), AsPtr(time.Sunday)},
+			entries: ListTimeEntry{
+				entry("05.10.2023", 50, "lunch"),
+				entry("05.10.2023", 600, "development"),
+				entry("06.10.2023", 725, "vacation"),
+			},
+			days: []SingleEntry{
+				{date: date("05.10.2023"), duration: 600},
+				{date: date("06.10.2023"), dtype: VACATION_DAY},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				ExcludedWeekdays: tt.excluded,
+				ExcludedTasks:    &ListString{AsPtr("lunch")},
+				TaskDayTypes:     DayTypeMap{"vacation": VACATION_DAY},
+			}
+			arr, err := AggregateDays(config, &tt.entries)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(*arr) != len(tt.days) {
+				t.Fatalf("got %v days, want %v", len(*arr), len(tt.days))
+			}
+			for i, want := range tt.days {
+				got := (*arr)[i]
+				if !got.date.Equal(want.date) || got.duration != want.duration || got.dtype != want.dtype {
+					t.Errorf("day %v: got %s %v (%v), want %s %v (%v)", i, got.date.Format("02.01.2006"), got.duration, got.dtype, want.date.Format("02.01.2006"), want.duration, want.dtype)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitAtMidnight(t *testing.T) {
+	tests := []struct {
+		name      string
+		start     string
+		d         time.Duration
+		durations []time.Duration
+	}{
+		{name: "same day", start: "2023-10-05 08:00", d: 8 * time.Hour, durations: []time.Duration{8 * time.Hour}},
+		{name: "past midnight", start: "2023-10-05 20:00", d: 6*time.Hour + 30*time.Minute, durations: []time.Duration{4 * time.Hour, 2*time.Hour + 30*time.Minute}},
+		{name: "over two midnights", start: "2023-10-05 23:00", d: 26 * time.Hour, durations: []time.Duration{time.Hour, 24 * time.Hour, time.Hour}},
+		{name: "zero", start: "2023-10-05 08:00", durations: []time.Duration{0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, _ := time.Parse("2006-01-02 15:04", tt.start)
+			dates, durations := SplitAtMidnight(start, tt.d)
+			if len(dates) != len(tt.durations) || len(durations) != len(tt.durations) {
+				t.Fatalf("got %v parts, want %v", len(durations), len(tt.durations))
+			}
+			for i, want := range tt.durations {
+				if durations[i] != want {
+					t.Errorf("part %v: got %v, want %v", i, durations[i], want)
+				}
+				if wantDate := start.AddDate(0, 0, i).Truncate(24 * time.Hour); !dates[i].Equal(wantDate) {
+					t.Errorf("part %v: got date %v, want %v", i, dates[i], wantDate)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Parse command line flags
+// Flags mapped to config keys override the values from config file
+func ParseArgs(arguments []string) (args *Args, err error) {
+	fs := flag.NewFlagSet(filepath.Base(os.Args[0]), flag.ContinueOnError)
+
+	args = &Args{Overrides: StringPtrMap{}}
+
+	configPath := fs.String(ARG_CONFIG_STR, "", fmt.Sprintf("Path to config file (default: %s next to executable)", CONFIG_FILE))
+	fs.BoolVar(&args.Once, ARG_ONCE_STR, false, "Run once without the rerun prompt, exit code tells the result")
+	fs.BoolVar(&args.Export, ARG_EXPORT_STR, false, "Export report into export dir instead of stdout (with -once)")
+
+	// Values are read back in Visit, only explicitly set flags are needed
+	fs.String(ARG_IMPORT_STR, "", "Path to import file")
+	fs.String(ARG_MODE_STR, "", "Operation mode: check|report|fix|generate")
+	fs.String(ARG_FILE_TYPE_STR, "", "Import file type: "+*ImporterNames())
+	fs.String(ARG_EXPORT_DIR_STR, "", "Directory for exported reports")
+	fs.String(ARG_DAILY_HOURS_STR, "", "Required daily work hours, e.g. 7,25")
+	fs.String(ARG_FORMAT_STR, "", "Report output formats, comma separated: "+*ExporterNames())
+	fs.Bool(ARG_CSV_WEEKLY_STR, false, "Write also weekly summary with csv format")
+	fs.String(ARG_FROM_STR, "", "First day shown in report, in config date layout (default: first imported day)")
+	fs.String(ARG_TO_STR, "", "Last day shown in report, in config date layout (default: last imported day)")
+	fs.String(ARG_JOURNAL_STR, "", fmt.Sprintf("Path to punch clock journal (default: %s next to config)", JOURNAL_FILE))
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [command] [flags]\n", fs.Name())
+		fmt.Fprintf(fs.Output(), "Punch clock commands: %s\n", *SortedKeys(JournalCommandMapping, AsPtr("|")))
+		fs.PrintDefaults()
+	}
+
+	if err = fs.Parse(arguments); err != nil {
+		return nil, err
+	}
+
+	// Punch clock command, flags may follow it
+	if fs.NArg() > 0 {
+		if _, ok := JournalCommandMapping[strings.ToLower(fs.Arg(0))]; ok {
+			args.Command = AsPtr(strings.ToLower(fs.Arg(0)))
+			if err = fs.Parse(fs.Args()[1:]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if fs.NArg() > 0 {
+		fmt.Printf("ERROR: Unexpected command line arguments: %s\n", strings.Join(fs.Args(), " "))
+		return nil, errors.New("unexpected command line arguments")
+	}
+
+	// Only flags explicitly set override config values
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == ARG_CONFIG_STR {
+			args.ConfigPath = AsPtr(strings.TrimSpace(*configPath))
+			return
+		}
+		key, ok := ArgConfigMapping[f.Name]
+		if !ok {
+			return
+		}
+		val := strings.TrimSpace(f.Value.String())
+		// Keep in line with config file values
+		if f.Name == ARG_MODE_STR || f.Name == ARG_FILE_TYPE_STR || f.Name == ARG_FORMAT_STR {
+			val = strings.ToLower(val)
+		}
+		args.Overrides[*key] = &val
+	})
+
+	return args, nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments []string
+		config    *string
+		overrides map[string]string
+		once      bool
+		export    bool
+		wantErr   bool
+	}{
+		{
+			name:      "no flags",
+			arguments: []string{},
+			overrides: map[string]string{},
+		},
+		{
+			name:      "config and run once",
+			arguments: []string{"-config", "/tmp/config.txt", "-once", "-export"},
+			config:    AsPtr("/tmp/config.txt"),
+			overrides: map[string]string{},
+			once:      true,
+			export:    true,
+		},
+		{
+			name:      "overrides keep path case, lowercase mode and type",
+			arguments: []string{"-import", " ./Samples/Report.txt ", "-mode", "CHECK", "-file-type", "Custom", "-daily-hours", "7,5"},
+			overrides: map[string]string{
+				CNF_IMPORT_PATH_STR: "./Samples/Report.txt",
+				CNF_MODE_STR:        "check",
+				CNF_FILE_TYPE_STR:   "custom",
+				CNF_DAILY_HOURS_STR: "7,5",
+			},
+		},
+		{
+			name:      "report range keeps date case",
+			arguments: []string{"-from", "01.Jun.2023", "-to", " 31.Dec.2023 "},
+			overrides: map[string]string{
+				CNF_REPORT_FROM_STR: "01.Jun.2023",
+				CNF_REPORT_TO_STR:   "31.Dec.2023",
+			},
+		},
+		{
+			name:      "unknown flag",
+			arguments: []string{"-unknown"},
+			wantErr:   true,
+		},
+		{
+			name:      "unexpected argument",
+			arguments: []string{"-once", "extra"},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := ParseArgs(tt.arguments)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (args.ConfigPath == nil) != (tt.config == nil) || (args.ConfigPath != nil && *args.ConfigPath != *tt.config) {
+				t.Errorf("got config path %v, want %v", args.ConfigPath, tt.config)
+			}
+			if args.Once != tt.once || args.Export != tt.export {
+				t.Errorf("got once %v export %v, want %v %v", args.Once, args.Export, tt.once, tt.export)
+			}
+			if len(args.Overrides) != len(tt.overrides) {
+				t.Fatalf("got %v overrides, want %v", len(args.Overrides), len(tt.overrides))
+			}
+			for k, want := range tt.overrides {
+				if got := args.Overrides[k]; got == nil || *got != want {
+					t.Errorf("override %s: got %v, want %s", k, got, want)
+				}
+			}
+		})
+	}
+}
@@ -2,10 +2,12 @@ package main
 
 import (
 	"bufio"
+	"encoding/csv"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -34,46 +36,29 @@ func WarnEmpty(k *string, v *string) {
 	fmt.Printf("WARNING: Failed to parse '%s' from config. Found empty value(s) in: '%s' defined in config file.\n", *k, *v)
 }
 
-func ParseValidateConfig() (config *Config, err error) {
-	path, err := os.Executable()
-
-	if err != nil {
-		fmt.Println("ERROR: Could not get current executable path. Err:", err.Error())
-		return
-	}
-
-	// Get current executable dir, parse, conv to abs, validate
-	path = filepath.Dir(path)
-	path, err = filepath.Abs(path)
-
-	if err != nil {
-		fmt.Println("ERROR: Could not get current working directory. Err:", err.Error())
-		return
-	}
-
-	f, err := os.Open(filepath.Join(path, CONFIG_FILE))
+// Read raw key-value pairs from config file
+// Lines after a [section] header are collected raw into the section
+func ReadConfigFile(configPath *string) (configMapping StringPtrMap, sections SectionMap, err error) {
+	f, err := os.Open(*configPath)
 
 	if err != nil {
-		fmt.Printf("ERROR: Failed to open config (path: %s) Err: %s\n", path, err.Error())
+		fmt.Printf("ERROR: Failed to open config (path: %s) Err: %s\n", *configPath, err.Error())
 		return
 	}
 
 	scanner := bufio.NewScanner(f)
 
-	if err != nil {
-		fmt.Printf("ERROR: Could not open or parse config file or config is invalid. "+
-			"Double check exists file: %s in current directory. Err: %s\n", CONFIG_FILE, err)
-		f.Close()
-		return
-	}
+	configMapping = EmptyConfigurationMapping()
+	sections = SectionMap{}
 
-	var configMapping = EmptyConfigurationMapping()
+	// Current section, nil for plain key-value pairs
+	var section *ListString
 
 	for scanner.Scan() {
 		if err = scanner.Err(); err != nil {
 			fmt.Println("ERROR: Failed to read line from config. Err:", err.Error())
 			f.Close()
-			return
+			return nil, nil, err
 		}
 
 		// Trim spaces from ends
@@ -84,20 +69,43 @@ func ParseValidateConfig() (config *Config, err error) {
 			continue
 		}
 
+		// Section header: [name]
+		if strings.HasPrefix(rawstr, "[") && strings.HasSuffix(rawstr, "]") {
+			name := strings.ToLower(strings.TrimSpace(StrRemoveBrackets(&rawstr)))
+			if sections[name] != nil {
+				fmt.Println("ERROR: Duplicate section in config:", rawstr)
+				f.Close()
+				return nil, nil, errors.New("detected duplicate section in config file")
+			}
+			section = AsPtr(make(ListString, 0))
+			sections[name] = section
+			continue
+		}
+
+		if section != nil {
+			// Kept as written, e.g. dates may contain month names of date layout
+			*section = append(*section, AsPtr(rawstr))
+			continue
+		}
+
 		raw := strings.Split(rawstr, "=")
 
 		if len(raw) != 2 {
 			fmt.Println("ERROR: Invalid line in config:", rawstr)
 			f.Close()
-			return nil, errors.New("invalid line in config file")
+			return nil, nil, errors.New("invalid line in config file")
 		}
 
 		key := strings.ToLower(strings.TrimSpace(raw[0]))
-		val := strings.ToLower(strings.TrimSpace(raw[1]))
+		val := strings.TrimSpace(raw[1])
+		// Paths and date layout are case sensitive
+		if !ValueInArray(&key, &ConfigCaseSensitiveKeys) {
+			val = strings.ToLower(val)
+		}
 
 		if configMapping[key] != nil {
 			f.Close()
-			return nil, errors.New("detected duplicate config key in config file")
+			return nil, nil, errors.New("detected duplicate config key in config file")
 		}
 
 		configMapping[key] = &val
@@ -106,42 +114,319 @@ func ParseValidateConfig() (config *Config, err error) {
 	// Close the file after parsed through
 	if err = f.Close(); err != nil {
 		fmt.Println("ERROR: Failed to close config file after reading:", err.Error())
-		return
+		return nil, nil, err
+	}
+
+	return configMapping, sections, nil
+}
+
+// Parse comma separated list of unique values
+// Returns nil list if no values
+func ParseConfigList(k *string, v *string) (list *ListString, err error) {
+	// Convert to array
+	// "value one,value two,value three, ..."
+	return configListValues(k, v, strings.Split(*v, ","))
+}
+
+// Comma separated list where values containing commas are quoted
+// value one, "value, two", ...
+func ParseQuotedConfigList(k *string, v *string) (list *ListString, err error) {
+	reader := csv.NewReader(strings.NewReader(*v))
+	reader.TrimLeadingSpace = true
+	val, err := reader.Read()
+	if err != nil {
+		return nil, ConfigErrorParse(k, v, err)
+	}
+	return configListValues(k, v, val)
+}
+
+// Trimmed non-empty values of a list, duplicates are errors
+func configListValues(k *string, v *string, val []string) (list *ListString, err error) {
+	if len(val) <= 0 {
+		WarnEmpty(k, v)
+		return nil, nil
+	}
+	// 0 => we dont know in advance how many there would be
+	list = AsPtr(make(ListString, 0))
+	for _, e := range val {
+		// "   " => ""
+		// " value one " => "value one"
+		vval := strings.TrimSpace(e)
+		if len(vval) <= 0 {
+			WarnEmpty(k, v)
+			continue
+		}
+		if SliceContains(list, &vval) {
+			return nil, ConfigErrorDuplicate(k, v)
+		}
+		*list = append(*list, &vval)
+	}
+	return list, nil
+}
+
+// Path is used as is if absolute or found relative to working dir
+// Otherwise relative to base dir, file does not need to exist yet
+func ResolveNewFilePath(v *string, base *string) *string {
+	if filepath.IsAbs(*v) {
+		return v
+	}
+	if stat, err := os.Stat(*v); err == nil && stat.Mode().IsRegular() {
+		return v
+	}
+	return AsPtr(filepath.Join(*base, *v))
+}
+
+// Parse comma separated import paths, each optionally prefixed with file type: <type>:<path>
+// Paths containing commas are quoted as a whole, e.g. "toggl_export:C:\Exports, 2024\toggl.csv"
+// Paths may be glob patterns, tried as is, then relative to base dir
+// Files without prefix get the file type from config
+func ParseImportPaths(k *string, v *string, base *string) (files *ListImportFile, err error) {
+	list, err := ParseQuotedConfigList(k, v)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil || len(*list) <= 0 {
+		return nil, ConfigErrorMissing(k)
+	}
+
+	files = AsPtr(make(ListImportFile, 0, len(*list)))
+
+	for _, e := range *list {
+		pattern := *e
+		var fileType *string
+		var importer Importer
+
+		// Prefix has to be a known file type, e.g. drive letter is part of the path
+		if name, rest, found := strings.Cut(pattern, ":"); found {
+			if imp, ok := ImporterRegistry[strings.ToLower(strings.TrimSpace(name))]; ok {
+				fileType = AsPtr(strings.ToLower(strings.TrimSpace(name)))
+				importer = imp
+				pattern = strings.TrimSpace(rest)
+			}
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, ConfigErrorParse(k, e, err)
+		}
+		if len(matches) <= 0 && !filepath.IsAbs(pattern) {
+			// Try relative path to config dir
+			matches, err = filepath.Glob(filepath.Join(*base, pattern))
+			if err != nil {
+				return nil, ConfigErrorParse(k, e, err)
+			}
+		}
+		if len(matches) <= 0 {
+			return nil, ConfigErrorParse(k, e, errors.New("no import files found"))
+		}
+
+		for _, m := range matches {
+			stat, err := os.Stat(m)
+			if err != nil {
+				return nil, ConfigErrorParse(k, e, err)
+			}
+			if !stat.Mode().IsRegular() {
+				fmt.Printf("ERROR: Import file path not pointing to a regular file: %s. Double check import file path in config.\n", m)
+				return nil, errors.New("import file path is not regular file")
+			}
+			if slices.ContainsFunc(*files, func(f *ImportFile) bool { return *f.path == m }) {
+				return nil, ConfigErrorDuplicate(k, v)
+			}
+			*files = append(*files, &ImportFile{path: AsPtr(m), name: AsPtr(stat.Name()), fileType: fileType, importer: importer})
+		}
+	}
+
+	return files, nil
+}
+
+// Locate existing regular file
+// Path is tried as is, then relative to base dir
+func ResolveFilePath(v *string, base *string) (*string, error) {
+	p := *v
+	stat, err := os.Stat(p)
+	if err != nil {
+		p = filepath.Join(*base, *v)
+		stat, err = os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !stat.Mode().IsRegular() {
+		return nil, errors.New("path is not a regular file")
+	}
+	return &p, nil
+}
+
+// Parse contract lines from config section
+// Line format: <from> - <to> [weekday,...] = <daily hours>
+// Either date can be left out for open ended range
+func ParseContracts(lines *ListString, layout *string) (contracts *ListContract, err error) {
+	k := "[" + CNF_CONTRACTS_SECTION_STR + "]"
+	contracts = AsPtr(make(ListContract, 0, len(*lines)))
+
+	parseDate := func(str string) (*time.Time, error) {
+		if str == "" {
+			return nil, nil
+		}
+		date, err := time.Parse(*layout, str)
+		if err != nil {
+			return nil, err
+		}
+		return AsPtr(DateKey(date)), nil
+	}
+
+	for _, line := range *lines {
+		raw := strings.Split(*line, "=")
+		if len(raw) != 2 {
+			return nil, ConfigErrorParse(&k, line, errors.New("expected format: <from> - <to> [weekday] = <hours>"))
+		}
+
+		hours, err := ParseHours(AsPtr(strings.TrimSpace(raw[1])))
+		if err != nil {
+			return nil, ConfigErrorParse(&k, line, err)
+		}
+		if hours < 0 || hours > 24*HOURS_SCALE {
+			return nil, ConfigErrorParse(&k, line, errors.New("daily hours out of range 0-24"))
+		}
+
+		// Range separator has to be a standalone dash
+		fields := strings.Fields(raw[0])
+		sep := slices.Index(fields, "-")
+		if sep < 0 {
+			return nil, ConfigErrorParse(&k, line, errors.New("date range separator ' - ' missing"))
+		}
+
+		// Optional weekdays after the range, e.g. mon,tue or Mon, Tue
+		weekdays := ListWeekday{nil}
+		rest := fields[sep+1:]
+		for i, f := range rest {
+			if _, ok := ConfigWeekdayMapping[strings.ToLower(strings.TrimSpace(strings.Split(f, ",")[0]))]; !ok {
+				continue
+			}
+			weekdays = make(ListWeekday, 0, 7)
+			for _, e := range strings.Split(strings.Join(rest[i:], " "), ",") {
+				e = strings.ToLower(strings.TrimSpace(e))
+				wd, err := ParseWeekday(&e)
+				if err != nil {
+					return nil, ConfigErrorParse(&k, line, err)
+				}
+				weekdays = append(weekdays, wd)
+			}
+			rest = rest[:i]
+			break
+		}
+
+		from, err := parseDate(strings.Join(fields[:sep], " "))
+		if err != nil {
+			return nil, ConfigErrorParse(&k, line, err)
+		}
+		to, err := parseDate(strings.Join(rest, " "))
+		if err != nil {
+			return nil, ConfigErrorParse(&k, line, err)
+		}
+		if from != nil && to != nil && to.Before(*from) {
+			return nil, ConfigErrorParse(&k, line, errors.New("range end before start"))
+		}
+
+		for _, wd := range weekdays {
+			*contracts = append(*contracts, &Contract{from: from, to: to, weekday: wd, hours: hours})
+		}
+	}
+
+	return contracts, nil
+}
+
+// Config file from flag, otherwise next to executable
+func ConfigFilePath(args *Args) (configPath string, err error) {
+	if args != nil && args.ConfigPath != nil {
+		configPath, err = filepath.Abs(*args.ConfigPath)
+		if err != nil {
+			fmt.Println("ERROR: Could not resolve config file path. Err:", err.Error())
+			return
+		}
+		return configPath, nil
+	}
+
+	path, err := os.Executable()
+
+	if err != nil {
+		fmt.Println("ERROR: Could not get current executable path. Err:", err.Error())
+		return "", err
+	}
+
+	// Get current executable dir, parse, conv to abs, validate
+	path = filepath.Dir(path)
+	path, err = filepath.Abs(path)
+
+	if err != nil {
+		fmt.Println("ERROR: Could not get current working directory. Err:", err.Error())
+		return "", err
+	}
+
+	return filepath.Join(path, CONFIG_FILE), nil
+}
+
+func ParseValidateConfig(args *Args) (config *Config, err error) {
+	configPath, err := ConfigFilePath(args)
+	if err != nil {
+		return nil, err
+	}
+
+	// Relative paths in config are resolved against the config file dir
+	path := filepath.Dir(configPath)
+
+	configMapping, sections, err := ReadConfigFile(&configPath)
+
+	if err != nil {
+		// Default config file may be omitted if everything is given as flags
+		if args == nil || args.ConfigPath != nil || len(args.Overrides) <= 0 || !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		fmt.Printf("WARNING: Config file (path: %s) not found. Using command line flags only.\n", configPath)
+		configMapping = EmptyConfigurationMapping()
+		sections = SectionMap{}
 	}
 
-	config = &Config{}
+	// Command line flags override config file values
+	if args != nil {
+		for k, v := range args.Overrides {
+			configMapping[k] = v
+		}
+	}
+
+	config = &Config{
+		ClockifyColumns: NewClockifyColumnAliases(),
+		TaskDayTypes:    DayTypeMap{},
+	}
+
+	// Holiday files can be parsed only after date layout known
+	var holidayRules HolidayRuleFunc
+	var holidayCalendar bool
+	var holidayFiles *ListString
+	var absencesFile *string
+	var reportFrom, reportTo *string
 
 	for k, v := range configMapping {
 		switch k {
 		case CNF_IMPORT_PATH_STR:
-			// Compulsory field
+			// Compulsory field, except journal file type defaults to journal path
+			// Checked after all keys are known
 			if v == nil {
-				return nil, ConfigErrorMissing(&k)
+				continue
 			}
-			// Collect Imported filename for later exporting purposes
-			// Assert: path = abspath or path = relpath to executable
-			// Assert: path == regular file, exists
-			stat, err := os.Stat(*v)
+			config.ImportFiles, err = ParseImportPaths(&k, v, &path)
 			if err != nil {
-				// Try relative path to executable path
-				stat, err = os.Stat(filepath.Join(path, *v))
-				if err != nil {
-					return nil, ConfigErrorParse(&k, v, err)
-				}
+				return nil, err
 			}
-			if !stat.Mode().IsRegular() {
-				println("ERROR: Import file path not pointing to a regular file. Double check import file path in config.")
-				return nil, errors.New("import file path is not regular file")
-			}
-			config.ImportFilePath = v
-			config.ImportFileName = AsPtr(stat.Name())
 		case CNF_EXPORT_PATH_STR:
 			// Optional field
 			if v != nil {
-				stat, err := os.Stat(*v)
+				dir := *v
+				stat, err := os.Stat(dir)
 				if err != nil {
 					// Try relative path to executable path
-					stat, err = os.Stat(filepath.Join(path, *v))
+					dir = filepath.Join(path, *v)
+					stat, err = os.Stat(dir)
 					if err != nil {
 						return nil, ConfigErrorParse(&k, v, err)
 					}
@@ -150,17 +435,23 @@ func ParseValidateConfig() (config *Config, err error) {
 					println("ERROR: Export file path not pointing to a directory. Double check export file path.")
 					return nil, errors.New("export file path is not a directory")
 				}
-				config.ExportFileName = AsPtr(fmt.Sprintf("Report_%s.txt", time.Now().Format("2006-01-02")))
-				config.ExportFilePath = AsPtr(filepath.Join(*v, *config.ExportFileName))
+				config.ExportDir = &dir
+				config.ExportFileName = ReportFileName(nil, "txt")
+				config.ExportFilePath = AsPtr(filepath.Join(dir, *config.ExportFileName))
 			}
 		case CNF_CSV_DELIM_STR:
+			// Optional field, defaults to comma
 			if v == nil {
-				return nil, ConfigErrorMissing(&k)
+				v = AsPtr(DEFAULT_CSV_DELIMITER)
+			}
+			config.CsvDelimiter, err = ParseCsvDelimiter(v)
+			if err != nil {
+				return nil, ConfigErrorParse(&k, v, err)
 			}
-			config.CsvDelimiter = v
 		case CNF_DATE_PARSE_STR:
+			// Optional field, defaults to dd.mm.yyyy
 			if v == nil {
-				return nil, ConfigErrorMissing(&k)
+				v = AsPtr(DEFAULT_DATE_LAYOUT)
 			}
 			config.DateParseLayout = v
 		case CNF_MODE_STR:
@@ -175,22 +466,23 @@ func ParseValidateConfig() (config *Config, err error) {
 			if v == nil {
 				return nil, ConfigErrorMissing(&k)
 			}
-			config.IfType, err = ParseInputFileType(v)
+			config.Importer, err = ParseImporter(v)
 			if err != nil {
 				return nil, ConfigErrorParse(&k, v, err)
 			}
+			config.FileType = v
 		case CNF_DAILY_HOURS_STR:
 			if v == nil {
 				return nil, ConfigErrorMissing(&k)
 			}
-			config.DailyHours, err = strconv.ParseFloat(StrFloatFiToUs(v), 64)
+			config.DailyHours, err = ParseHours(v)
 			if err != nil {
 				return nil, ConfigErrorParse(&k, v, err)
 			}
 		case CNF_INITIAL_BALANCE_STR:
 			// Optional field
 			if v != nil {
-				conv, err := strconv.ParseFloat(StrFloatFiToUs(v), 64)
+				conv, err := ParseHours(v)
 				if err != nil {
 					return nil, ConfigErrorParse(&k, v, err)
 				}
@@ -222,29 +514,120 @@ func ParseValidateConfig() (config *Config, err error) {
 		case CNF_EXCLUDED_TASKS_STR:
 			// Optional field
 			if v != nil {
-				// Convert to array
-				// "value one,value two,value three, ..."
-				val := strings.Split(*v, ",")
-				if len(val) <= 0 {
-					WarnEmpty(&k, v)
-				} else {
-					// 0 => we dont know in advance how many there would be
-					config.ExcludedTasks = AsPtr(make(ListString, 0))
-					for _, e := range val {
-						// "   " => ""
-						// " value one " => "value one"
-						vval := strings.TrimSpace(e)
-						if len(vval) <= 0 {
-							WarnEmpty(&k, v)
-							continue
-						}
-						if SliceContains(config.ExcludedTasks, &vval) {
+				config.ExcludedTasks, err = ParseConfigList(&k, v)
+				if err != nil {
+					return nil, err
+				}
+			}
+		case CNF_CLOCKIFY_TASK_STR, CNF_CLOCKIFY_DATE_STR, CNF_CLOCKIFY_DURATION_STR, CNF_CLOCKIFY_PROJECT_STR:
+			// Optional field, additional header names for the column
+			if v != nil {
+				aliases, err := ParseConfigList(&k, v)
+				if err != nil {
+					return nil, err
+				}
+				if aliases != nil {
+					col := ConfigClockifyColumnMapping[k]
+					*(*config.ClockifyColumns)[col] = append(*(*config.ClockifyColumns)[col], *aliases...)
+				}
+			}
+		case CNF_HOLIDAY_CALENDAR_STR:
+			// Optional field
+			if v != nil {
+				rules, ok := HolidayRulesMapping[*v]
+				if !ok {
+					return nil, ConfigErrorParse(&k, v, errors.New("unknown holiday calendar"))
+				}
+				holidayRules = rules
+				holidayCalendar = true
+			}
+		case CNF_HOLIDAY_FILES_STR:
+			// Optional field
+			if v != nil {
+				holidayFiles, err = ParseConfigList(&k, v)
+				if err != nil {
+					return nil, err
+				}
+				for _, e := range *holidayFiles {
+					// Assert: path = abspath or path = relpath to config dir
+					p, err := ResolveFilePath(e, &path)
+					if err != nil {
+						return nil, ConfigErrorParse(&k, e, err)
+					}
+					*e = *p
+				}
+			}
+		case CNF_VACATION_TASKS_STR, CNF_SICK_TASKS_STR, CNF_FLEX_TASKS_STR, CNF_UNPAID_TASKS_STR:
+			// Optional field, task or project names marking absence days
+			if v != nil {
+				names, err := ParseConfigList(&k, v)
+				if err != nil {
+					return nil, err
+				}
+				if names != nil {
+					for _, e := range *names {
+						if _, ok := config.TaskDayTypes[*e]; ok {
 							return nil, ConfigErrorDuplicate(&k, v)
 						}
-						*config.ExcludedTasks = append(*config.ExcludedTasks, &vval)
+						config.TaskDayTypes[*e] = ConfigDayTypeTasksMapping[k]
+					}
+				}
+			}
+		case CNF_ABSENCES_FILE_STR:
+			// Optional field
+			if v != nil {
+				absencesFile, err = ResolveFilePath(v, &path)
+				if err != nil {
+					return nil, ConfigErrorParse(&k, v, err)
+				}
+			}
+		case CNF_EXPORT_FORMAT_STR:
+			// Optional field, defaults to text
+			if v != nil {
+				config.ExportFormats, err = ParseConfigList(&k, v)
+				if err != nil {
+					return nil, err
+				}
+				if config.ExportFormats != nil {
+					for _, e := range *config.ExportFormats {
+						if _, err := ParseExporter(e); err != nil {
+							return nil, ConfigErrorParse(&k, e, err)
+						}
 					}
 				}
 			}
+		case CNF_CSV_WEEKLY_STR:
+			// Optional field
+			if v != nil {
+				config.CsvWeekly, err = strconv.ParseBool(*v)
+				if err != nil {
+					return nil, ConfigErrorParse(&k, v, err)
+				}
+			}
+		case CNF_JOURNAL_PATH_STR:
+			// Optional field, journal may not exist yet
+			if v != nil {
+				config.JournalPath = ResolveNewFilePath(v, &path)
+			}
+		case CNF_SNAPSHOT_PATH_STR:
+			// Optional field, created on first report run
+			if v != nil {
+				config.SnapshotPath = ResolveNewFilePath(v, &path)
+			}
+		case CNF_REPORT_FROM_STR:
+			// Optional field, parsed after date layout known
+			reportFrom = v
+		case CNF_REPORT_TO_STR:
+			// Optional field, parsed after date layout known
+			reportTo = v
+		case CNF_SNAPSHOT_PERIOD_STR:
+			// Optional field, defaults to year
+			if v != nil {
+				config.SnapshotPeriod, err = ParseSnapshotPeriod(v)
+				if err != nil {
+					return nil, ConfigErrorParse(&k, v, err)
+				}
+			}
 		default:
 			// Improve backwards compatibility - ignore (yet) undefined keys
 			fmt.Printf("WARNING: Key in config is unknown: '%s'. Double check config file. Config value ignored.\n", k)
@@ -252,11 +635,114 @@ func ParseValidateConfig() (config *Config, err error) {
 		}
 	}
 
-	// If import file is exported/generated, there is no point doing any checking/validation
-	if config.Mode == CHECK_MODE && config.IfType == CLOCKIFY_FILE {
-		println("ERROR: Current selected mode and import file type are incompatible. " +
-			"Please update config: select either check mode with ANY CUSTOM import, or report mode with ANY import file.")
-		return nil, errors.New("incompatible mode and file")
+	if config.JournalPath == nil {
+		config.JournalPath = AsPtr(filepath.Join(path, JOURNAL_FILE))
+	}
+
+	if config.ImportFiles == nil {
+		k := CNF_IMPORT_PATH_STR
+		if _, ok := config.Importer.(*JournalImporter); !ok {
+			return nil, ConfigErrorMissing(&k)
+		}
+		config.ImportFiles = &ListImportFile{{path: config.JournalPath, name: AsPtr(filepath.Base(*config.JournalPath))}}
+	}
+
+	// Files without type prefix use the file type of config
+	for _, f := range *config.ImportFiles {
+		if f.importer == nil {
+			f.fileType = config.FileType
+			f.importer = config.Importer
+		}
+	}
+	config.ImportFilePath = (*config.ImportFiles)[0].path
+	config.ImportFileName = (*config.ImportFiles)[0].name
+
+	if holidayCalendar || (holidayFiles != nil && len(*holidayFiles) > 0) {
+		config.Holidays = NewHolidayCalendar(holidayRules)
+		if holidayFiles != nil {
+			for _, e := range *holidayFiles {
+				if err = config.Holidays.ReadFile(e, config.DateParseLayout); err != nil {
+					k := CNF_HOLIDAY_FILES_STR
+					return nil, ConfigErrorParse(&k, e, err)
+				}
+			}
+		}
+	}
+
+	if absencesFile != nil {
+		config.Absences, err = ReadAbsencesFile(absencesFile, config.DateParseLayout)
+		if err != nil {
+			k := CNF_ABSENCES_FILE_STR
+			return nil, ConfigErrorParse(&k, absencesFile, err)
+		}
+	}
+
+	// Report range, either bound can be left out
+	parseBound := func(k string, v *string) (*time.Time, error) {
+		if v == nil {
+			return nil, nil
+		}
+		date, err := time.Parse(*config.DateParseLayout, *v)
+		if err != nil {
+			return nil, ConfigErrorParse(&k, v, err)
+		}
+		return AsPtr(DateKey(date)), nil
+	}
+	if config.ReportFrom, err = parseBound(CNF_REPORT_FROM_STR, reportFrom); err != nil {
+		return nil, err
+	}
+	if config.ReportTo, err = parseBound(CNF_REPORT_TO_STR, reportTo); err != nil {
+		return nil, err
+	}
+	if config.ReportFrom != nil && config.ReportTo != nil && config.ReportTo.Before(*config.ReportFrom) {
+		k := CNF_REPORT_TO_STR
+		return nil, ConfigErrorParse(&k, reportTo, errors.New("report range end before start"))
+	}
+
+	// Sections depend on date layout, parse after all keys known
+	for name, lines := range sections {
+		switch name {
+		case CNF_CONTRACTS_SECTION_STR:
+			config.Contracts, err = ParseContracts(lines, config.DateParseLayout)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			fmt.Printf("WARNING: Section in config is unknown: '[%s]'. Double check config file. Section ignored.\n", name)
+		}
+	}
+
+	if config.ExportFormats == nil || len(*config.ExportFormats) <= 0 {
+		config.ExportFormats = &ListString{AsPtr(DEFAULT_EXPORT_FORMAT)}
+	}
+
+	// Mode has to be able to handle the entries of each file type
+	// e.g. exported file has no reported balance to check or fix
+	if handler, ok := ModeHandlerMapping[config.Mode]; ok {
+		for _, f := range *config.ImportFiles {
+			if slices.Contains(handler.kinds, f.importer.Kind()) {
+				continue
+			}
+			kinds := make(ListString, 0, len(handler.kinds))
+			for _, e := range handler.kinds {
+				kinds = append(kinds, ImportKindRevMapping[e])
+			}
+			fmt.Printf("ERROR: Current selected mode (%s) and import file type (%s) are incompatible. "+
+				"Mode requires a file type with %s entries, selected file type has %s entries.\n",
+				*OperationModeRevMapping[config.Mode], *f.fileType, *StringsJoin(&kinds, AsPtr(" or ")), *ImportKindRevMapping[f.importer.Kind()])
+			return nil, errors.New("incompatible mode and file")
+		}
+	}
+
+	// Weekly files carry their own running balance, they cannot be merged
+	if len(*config.ImportFiles) > 1 {
+		for _, f := range *config.ImportFiles {
+			if f.importer.Kind() == WEEK_IMPORT {
+				fmt.Printf("ERROR: Import file %s (type: %s) has weekly entries and cannot be merged with other import files. "+
+					"Double check import path in config.\n", *f.name, *f.fileType)
+				return nil, errors.New("weekly import file cannot be merged")
+			}
+		}
 	}
 
 	return config, nil
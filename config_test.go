@@ -0,0 +1,277 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReadConfigFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		values   map[string]string
+		sections map[string][]string
+		wantErr  bool
+	}{
+		{
+			name:    "keys and values lowercased",
+			content: "# comment\n\nMode = CHECK\nFile_Type = Custom\n",
+			values:  map[string]string{CNF_MODE_STR: "check", CNF_FILE_TYPE_STR: "custom"},
+		},
+		{
+			name:    "paths and date layout keep case",
+			content: "import_path = ./Samples/Manual_2023.txt\nexport_dir = C:\\Export\\Dir\ndate_layout = Jan 2, 2006\n",
+			values: map[string]string{
+				CNF_IMPORT_PATH_STR: "./Samples/Manual_2023.txt",
+				CNF_EXPORT_PATH_STR: "C:\\Export\\Dir",
+				CNF_DATE_PARSE_STR:  "Jan 2, 2006",
+			},
+		},
+		{
+			name:    "missing keys are nil",
+			content: "mode = report\n",
+			values:  map[string]string{CNF_MODE_STR: "report"},
+		},
+		{
+			name:     "section lines kept as written",
+			content:  "mode = check\n[Contracts]\nJan 1 2023 - Mar 31 2023 Mon, Tue = 7,5\n",
+			values:   map[string]string{CNF_MODE_STR: "check"},
+			sections: map[string][]string{CNF_CONTRACTS_SECTION_STR: {"Jan 1 2023 - Mar 31 2023 Mon, Tue = 7,5"}},
+		},
+		{
+			name:    "duplicate section",
+			content: "[contracts]\n[Contracts]\n",
+			wantErr: true,
+		},
+		{
+			name:    "duplicate key",
+			content: "mode = check\nmode = report\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid line",
+			content: "mode check\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), CONFIG_FILE)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			configMapping, sections, err := ReadConfigFile(&path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for k, v := range configMapping {
+				want, ok := tt.values[k]
+				if !ok {
+					if v != nil {
+						t.Errorf("key %s: got %s, want nil", k, *v)
+					}
+					continue
+				}
+				if v == nil || *v != want {
+					t.Errorf("key %s: got %v, want %s", k, v, want)
+				}
+			}
+			if len(sections) != len(tt.sections) {
+				t.Fatalf("got %v sections, want %v", len(sections), len(tt.sections))
+			}
+			for name, want := range tt.sections {
+				lines, ok := sections[name]
+				if !ok || len(*lines) != len(want) {
+					t.Fatalf("section %s: got %v, want %v", name, lines, want)
+				}
+				for i, l := range want {
+					if *(*lines)[i] != l {
+						t.Errorf("section %s line %v: got %s, want %s", name, i, *(*lines)[i], l)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParseContracts(t *testing.T) {
+	date := func(layout string, s string) *time.Time {
+		d, _ := time.Parse(layout, s)
+		return &d
+	}
+	dmy := "02.01.2006"
+
+	tests := []struct {
+		name      string
+		layout    string
+		lines     []string
+		contracts []Contract
+		wantErr   bool
+	}{
+		{
+			name:   "closed and open ranges",
+			layout: dmy,
+			lines:  []string{"01.01.2023 - 31.05.2023 = 7,5", "01.09.2023 - = 7,25", "- 31.12.2022 = 8"},
+			contracts: []Contract{
+				{from: date(dmy, "01.01.2023"), to: date(dmy, "31.05.2023"), hours: 750},
+				{from: date(dmy, "01.09.2023"), hours: 725},
+				{to: date(dmy, "31.12.2022"), hours: 800},
+			},
+		},
+		{
+			name:   "weekday list with spaces",
+			layout: dmy,
+			lines:  []string{"01.06.2023 - 31.08.2023 mon, Tue ,fri = 6"},
+			contracts: []Contract{
+				{from: date(dmy, "01.06.2023"), to: date(dmy, "31.08.2023"), weekday: AsPtr(time.Monday), hours: 600},
+				{from: date(dmy, "01.06.2023"), to: date(dmy, "31.08.2023"), weekday: AsPtr(time.Tuesday), hours: 600},
+				{from: date(dmy, "01.06.2023"), to: date(dmy, "31.08.2023"), weekday: AsPtr(time.Friday), hours: 600},
+			},
+		},
+		{
+			name:   "open end with weekday",
+			layout: dmy,
+			lines:  []string{"01.06.2023 - FRI = 6"},
+			contracts: []Contract{
+				{from: date(dmy, "01.06.2023"), weekday: AsPtr(time.Friday), hours: 600},
+			},
+		},
+		{
+			name:   "month names in date layout",
+			layout: "Jan 2 2006",
+			lines:  []string{"Jun 1 2023 - Aug 31 2023 Mon = 6"},
+			contracts: []Contract{
+				{from: date("Jan 2 2006", "Jun 1 2023"), to: date("Jan 2 2006", "Aug 31 2023"), weekday: AsPtr(time.Monday), hours: 600},
+			},
+		},
+		{
+			name:    "missing separator",
+			layout:  dmy,
+			lines:   []string{"01.01.2023 31.05.2023 = 7,5"},
+			wantErr: true,
+		},
+		{
+			name:    "end before start",
+			layout:  dmy,
+			lines:   []string{"01.06.2023 - 31.05.2023 = 7,5"},
+			wantErr: true,
+		},
+		{
+			name:    "hours out of range",
+			layout:  dmy,
+			lines:   []string{"01.06.2023 - = 25"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown weekday in list",
+			layout:  dmy,
+			lines:   []string{"01.06.2023 - mon, someday = 6"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := make(ListString, 0, len(tt.lines))
+			for _, l := range tt.lines {
+				lines = append(lines, AsPtr(l))
+			}
+			contracts, err := ParseContracts(&lines, &tt.layout)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(*contracts) != len(tt.contracts) {
+				t.Fatalf("got %v contracts, want %v", len(*contracts), len(tt.contracts))
+			}
+			sameDate := func(a, b *time.Time) bool {
+				return (a == nil) == (b == nil) && (a == nil || a.Equal(*b))
+			}
+			for i, want := range tt.contracts {
+				got := (*contracts)[i]
+				if !sameDate(got.from, want.from) || !sameDate(got.to, want.to) {
+					t.Errorf("contract %v: got range %v - %v, want %v - %v", i, got.from, got.to, want.from, want.to)
+				}
+				if (got.weekday == nil) != (want.weekday == nil) || (got.weekday != nil && *got.weekday != *want.weekday) {
+					t.Errorf("contract %v: got weekday %v, want %v", i, got.weekday, want.weekday)
+				}
+				if got.hours != want.hours {
+					t.Errorf("contract %v: got hours %v, want %v", i, got.hours, want.hours)
+				}
+			}
+		})
+	}
+}
+
+func TestParseImportPaths(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.csv", "b.csv", "Time, 2024.csv", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		files   []string
+		types   []string
+		wantErr bool
+	}{
+		{name: "single relative to base", value: "a.csv", files: []string{"a.csv"}, types: []string{""}},
+		{name: "list with type prefix", value: "a.csv, toggl_export:b.csv", files: []string{"a.csv", "b.csv"}, types: []string{"", "toggl_export"}},
+		{name: "prefix in any case", value: "Toggl_Export:b.csv", files: []string{"b.csv"}, types: []string{"toggl_export"}},
+		{name: "glob pattern", value: "*.csv", files: []string{"Time, 2024.csv", "a.csv", "b.csv"}, types: []string{"", "", ""}},
+		{name: "quoted path with comma", value: `a.csv, "toggl_export:Time, 2024.csv"`, files: []string{"a.csv", "Time, 2024.csv"}, types: []string{"", "toggl_export"}},
+		{name: "unknown prefix is part of path", value: "c:a.csv", wantErr: true},
+		{name: "unquoted path with comma", value: "Time, 2024.csv", wantErr: true},
+		{name: "unterminated quote", value: `"a.csv`, wantErr: true},
+		{name: "same file twice", value: "a.csv, *.csv", wantErr: true},
+		{name: "no files found", value: "*.ics", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := CNF_IMPORT_PATH_STR
+			files, err := ParseImportPaths(&k, &tt.value, &dir)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(*files) != len(tt.files) {
+				t.Fatalf("got %v files, want %v", len(*files), len(tt.files))
+			}
+			for i, f := range *files {
+				if *f.name != tt.files[i] {
+					t.Errorf("file %v: got %s, want %s", i, *f.name, tt.files[i])
+				}
+				fileType := ""
+				if f.fileType != nil {
+					fileType = *f.fileType
+				}
+				if fileType != tt.types[i] {
+					t.Errorf("file %v: got type %s, want %s", i, fileType, tt.types[i])
+				}
+			}
+		})
+	}
+}
@@ -1,173 +1,235 @@
 package main
 
 import (
-	"errors"
 	"fmt"
-	"time"
+	"io"
 )
 
-// Same logic output report to stdout or file
-// Always replace same export file
-// Prefer updating file, avoid piling up files
-func ExportClockifyReport(config *Config, global *Common, entries2 *ListSingleEntry, printfF FuncPrintf, printlnF FuncPrintln) error {
-	if printfF == nil || printlnF == nil {
-		fmt.Println("ERROR: Either of print functions not set for the output.")
-		return errors.New("either print function nil")
+// Add exporter for the export format name
+// Called from init functions of the exporters
+func RegisterExporter(name string, exporter Exporter) {
+	if _, ok := ExporterRegistry[name]; ok {
+		panic("exporter registered twice: " + name)
 	}
+	ExporterRegistry[name] = exporter
+}
 
-	// Keep track of some variables
-	var balance float64 = 0
-	if config.InitialBalance != nil {
-		balance += *config.InitialBalance
-	}
-	var weekWorked float64 = 0
-	var weekno uint16 = 0
-
-	var _, lastWeek = (*entries2)[0].date.ISOWeek()
-
-	// Debugging variables
-	// Remember to comment out time range
-	var lower *time.Time = nil
-	var upper *time.Time = nil
-	// lower = AsPtr(time.Parse(*config.DateParseLayout, "25.07.2023"))
-	// upper = AsPtr(time.Parse(*config.DateParseLayout, "10.08.2023"))
-
-	var cur *SingleEntry
-	printWeek := func(wk int) (printed bool) {
-		printed = false
-		weekno += 1
-		if (lower == nil && upper == nil) || (cur.date.After(*lower) && cur.date.Before(*upper)) {
-			_, _ = printlnF()
-			_, _ = printlnF()
-			_, _ = printlnF("********************")
-			_, _ = printfF("Week: %v (%v):\nWorked: %s\nWeek Diff: %s\nBalance: %s\n", weekno, lastWeek, *PlusSignIfNecessary(weekWorked), *PlusSignIfNecessary(weekWorked - global.weeklyHours), *PlusSignIfNecessary(balance))
-			_, _ = printlnF("********************")
-			_, _ = printlnF()
-			_, _ = printlnF()
-			printed = true
-		}
-		weekWorked = 0
-		return
-	}
+// Registered export format names in alphabetical order
+func ExporterNames() *string {
+	return SortedKeys(ExporterRegistry, AsPtr("|"))
+}
 
-	for i, e := range *entries2 {
-		// Keep track of last entry for printweek
-		cur = e
+// Human readable text output, the default format
+type TextExporter struct{}
 
-		// Get current week number
-		_, wk := e.date.ISOWeek()
+func init() {
+	RegisterExporter(DEFAULT_EXPORT_FORMAT, &TextExporter{})
+}
 
-		if wk != lastWeek {
-			printWeek(wk)
-		} else if (lower == nil && upper == nil) || (cur.date.After(*lower) && cur.date.Before(*upper)) {
-			_, _ = printlnF("--------------------")
-		}
+func (exp *TextExporter) Files(config *Config, global *Common, data *ExportData) ListExportFile {
+	file := &ExportFile{ext: "txt"}
+	switch {
+	case data.report != nil:
+		file.write = func(w io.Writer) error { return ExportClockifyReport(config, global, data.report, w) }
+	case data.weeks != nil:
+		file.write = func(w io.Writer) error { return ExportCustomReport(config, global, data.weeks, w) }
+	case data.check != nil:
+		file.suffix = AsPtr("check")
+		file.write = func(w io.Writer) error { return ExportCustomFile(config, global, data.check, w) }
+	default:
+		return nil
+	}
+	return ListExportFile{file}
+}
 
-		// Usually only mon-fri has daily hours limit
-		// Other days hours are counted as additional hours
-		var diff float64
-		if config.ExcludedWeekdays != nil && ValueInArray(AsPtr(e.date.Weekday()), config.ExcludedWeekdays) {
-			// Count any excluded days work as extra time
-			diff = e.duration
-		} else {
-			diff = e.duration - config.DailyHours
-		}
+// Same logic output report to stdout or file
+// Always replace same export file
+// Prefer updating file, avoid piling up files
+func ExportClockifyReport(config *Config, global *Common, report *Report, w io.Writer) error {
+	printfF, printlnF := WriterPrintf(w), WriterPrintln(w)
 
-		balance += diff
-		weekWorked += e.duration
+	printWeek := func(w *ReportWeek) {
+		_, _ = printlnF()
+		_, _ = printlnF()
+		_, _ = printlnF("********************")
+		_, _ = printfF("Week: %v (%v):\nWorked: %s\nWeek Diff: %s\nBalance: %s\n", w.index, w.week, *PlusSignIfNecessary(w.worked), *PlusSignIfNecessary(w.diff), *PlusSignIfNecessary(w.balance))
+		_, _ = printlnF("********************")
+		_, _ = printlnF()
+		_, _ = printlnF()
+	}
 
-		if (lower == nil && upper == nil) || (cur.date.After(*lower) && cur.date.Before(*upper)) {
-			_, _ = printfF("Entry Index: %v\nDate: %s\nWorked: %s\nDiff to limit: %s\nCurrent Balance: %s\n", i, e.date.Format(*config.DateParseLayout), *PlusSignIfNecessary(e.duration), *PlusSignIfNecessary(diff), *PlusSignIfNecessary(balance))
-		}
+	for wi, w := range report.weeks {
+		for di, d := range w.days {
+			// Week summary printed when the next week begins
+			if wi > 0 && di == 0 {
+				printWeek(report.weeks[wi-1])
+			} else {
+				_, _ = printlnF("--------------------")
+			}
 
-		lastWeek = wk
+			e := d.entry
+			_, _ = printfF("Entry Index: %v\nDate: %s\n", d.index, e.date.Format(*config.DateParseLayout))
+			if e.dtype != WORK_DAY {
+				_, _ = printfF("Day Type: %s\n", *DayTypeRevMapping[e.dtype])
+			}
+			if d.holiday != nil {
+				_, _ = printfF("Holiday: %s\n", *d.holiday.name)
+			}
+			_, _ = printfF("Worked: %s\nDiff to limit: %s\nCurrent Balance: %s\n", *PlusSignIfNecessary(e.duration), *PlusSignIfNecessary(d.diff), *PlusSignIfNecessary(d.balance))
+		}
 	}
 
-	if !printWeek(lastWeek) {
-		_, _ = printlnF()
-		_, _ = printlnF()
+	if len(report.weeks) > 0 {
+		printWeek(report.weeks[len(report.weeks)-1])
 	}
-	_, _ = printfF("Final Balance: %s\n", *PlusSignIfNecessary(balance))
+	_, _ = printfF("Final Balance: %s\n", *PlusSignIfNecessary(report.balance))
 
 	return nil
 }
 
-func ExportCustomFile(config *Config, global *Common, entries *ListWeekEntry) {
+// Check the custom file entries against expected values
+// Collects every discrepancy instead of stopping at the first
+func CheckCustomEntries(config *Config, global *Common, entries *ListWeekEntry) *CheckResult {
+	result := &CheckResult{
+		rows: make(ListCheckRow, 0, len(*entries)),
+	}
+
 	// Keep track of some variables
-	var balance float64 = 0
+	var balance Hours = 0
 	if config.InitialBalance != nil {
 		balance += *config.InitialBalance
 	}
+	reported := balance
 	var prevYear Year
 	var prevMonth Month
 
 	// Go through entries and check
 	// Verify against each entry recorded balance matches etc
 	for i, e := range *entries {
-		fmt.Printf("\nMonth: %v Year: %v\nWeek: %s\nWorked: %.2f\nDiff: %.2f\nReported Balance: %.2f\n", e.month, e.year, *e.trange, e.worked, e.diff, e.balance)
-		if i == 0 {
-			// if entry is FIRST only (not middle, last)
-			// All stuff specific to first entry
-			_ = 0
-		} else {
+		row := &CheckRow{index: i, entry: e, errors: make(ListString, 0)}
+		addError := func(format string, a ...any) {
+			row.errors = append(row.errors, AsPtr(fmt.Sprintf(format, a...)))
+			result.errorCount++
+		}
+
+		if i > 0 {
 			// if entry NOT first, can be last (is middle, last)
 			// All stuff specific to any NON-FIRST entry
 			if e.year != prevYear || e.month != prevMonth {
+				errorsBefore := len(row.errors)
 				if prevMonth == 12 {
 					// If previous month was 12, it should be next year 1
 					if e.year != prevYear+1 {
-						fmt.Printf("ERROR: Entry %v (%s): Year (%v) != Next Year (%v)\n", i, *e.trange, e.year, prevYear+1)
-						return
+						addError("Entry %v (%s): Year (%v) != Next Year (%v)", i, *e.trange, e.year, prevYear+1)
 					}
 					if e.month != 1 {
-						fmt.Printf("ERROR: Entry %v (%s): Month (%v) != Next Month (%v)\n", i, *e.trange, e.month, 1)
-						return
+						addError("Entry %v (%s): Month (%v) != Next Month (%v)", i, *e.trange, e.month, 1)
 					}
 				} else {
 					// IF month or year has changed since previous
 					// but its NOT next year
 					if e.year != prevYear {
-						fmt.Printf("ERROR: Entry %v (%s): Year (%v) != Expected Year (%v)\n", i, *e.trange, e.year, prevYear)
-						return
+						addError("Entry %v (%s): Year (%v) != Expected Year (%v)", i, *e.trange, e.year, prevYear)
 					}
 					if e.month != prevMonth+1 {
-						fmt.Printf("ERROR: Entry %v (%s): Month (%v) != Next Month (%v)\n", i, *e.trange, e.month, prevMonth+1)
-						return
+						addError("Entry %v (%s): Month (%v) != Next Month (%v)", i, *e.trange, e.month, prevMonth+1)
 					}
-
 				}
+				row.continuityError = len(row.errors) > errorsBefore
 			}
 		}
-		if i == len(*entries)-1 {
-			// if entry is LAST only (not first, middle)
-			// All stuff specific to last entry
-			_ = 0
-		} else {
-			// if entry NOT last, can be first (is first, middle)
-			// All stuff specific to any NON-LAST entry
-			_ = 0
-		}
+
 		// Stuff common to any entry
-		expDiff := e.worked - global.weeklyHours
-		if e.diff != expDiff {
-			fmt.Printf("ERROR: Entry %v (%s): Diff (%s) != Worked (%s) - Limit (%.2f) == Expected Diff (%s)\n", i, *e.trange, *PlusSignIfNecessary(e.diff), *PlusSignIfNecessary(e.worked), global.weeklyHours, *PlusSignIfNecessary(expDiff))
-			return
+		// Holidays reduce the weekly limit
+		row.required = WeekRequiredHours(config, global, e)
+		row.expectedDiff = e.worked - row.required
+		if e.diff != row.expectedDiff {
+			addError("Entry %v (%s): Diff (%s) != Worked (%s) - Limit (%s) == Expected Diff (%s)", i, *e.trange, *PlusSignIfNecessary(e.diff), *PlusSignIfNecessary(e.worked), row.required, *PlusSignIfNecessary(row.expectedDiff))
 		}
 
-		// Collect the current EXPECTED balance (troughout entries)
-		balance += e.worked - global.weeklyHours
-		fmt.Printf("Expected Balance: %s\n", *PlusSignIfNecessary(balance))
-
-		if balance != e.balance {
-			fmt.Printf("\nERROR: Entry %v (%s): Expected Balance (%s) != Reported Balance (%s)\n", i, *e.trange, *PlusSignIfNecessary(balance), *PlusSignIfNecessary(e.balance))
-			return
+		// Compare against the previous reported balance
+		// A single mismatch is reported once, not again on every later entry
+		row.expectedBalance = reported + row.expectedDiff
+		if row.expectedBalance != e.balance {
+			addError("Entry %v (%s): Expected Balance (%s) != Reported Balance (%s)", i, *e.trange, *PlusSignIfNecessary(row.expectedBalance), *PlusSignIfNecessary(e.balance))
+			if result.divergence == nil {
+				result.divergence = row
+			}
 		}
+		reported = e.balance
+
+		// Collect the correct balance troughout entries for fixing
+		balance += row.expectedDiff
+		row.recalculatedBalance = balance
+
+		result.rows = append(result.rows, row)
 
 		prevYear = e.year
 		prevMonth = e.month
 	}
 
-	fmt.Println()
-	fmt.Printf("Final Balance: %s\n", *PlusSignIfNecessary(balance))
-	fmt.Println()
+	result.balance = balance
+
+	return result
+}
+
+// Print the check results of every entry and the summary
+func ExportCustomFile(config *Config, global *Common, result *CheckResult, w io.Writer) error {
+	printfF, printlnF := WriterPrintf(w), WriterPrintln(w)
+
+	for _, row := range result.rows {
+		e := row.entry
+		_, _ = printfF("\nMonth: %v Year: %v\nWeek: %s\nWorked: %s\nDiff: %s\nReported Balance: %s\n", e.month, e.year, *e.trange, e.worked, e.diff, e.balance)
+		if row.required != global.weeklyHours {
+			_, _ = printfF("Required: %s\n", row.required)
+		}
+		_, _ = printfF("Expected Balance: %s\n", *PlusSignIfNecessary(row.expectedBalance))
+		for _, msg := range row.errors {
+			_, _ = printfF("ERROR: %s\n", *msg)
+		}
+	}
+
+	_, _ = printlnF()
+	_, _ = printlnF("********************")
+	_, _ = printfF("Entries Checked: %v\n", len(result.rows))
+	_, _ = printfF("Errors Found: %v\n", result.errorCount)
+	if result.divergence != nil {
+		d := result.divergence
+		_, _ = printfF("Balance Diverged First: Entry %v (%s) Month: %v Year: %v: Expected Balance (%s) != Reported Balance (%s)\n", d.index, *d.entry.trange, d.entry.month, d.entry.year, *PlusSignIfNecessary(d.expectedBalance), *PlusSignIfNecessary(d.entry.balance))
+	}
+	_, _ = printlnF("********************")
+	_, _ = printlnF()
+	_, _ = printfF("Final Balance: %s\n", *PlusSignIfNecessary(result.balance))
+	_, _ = printlnF()
+
+	return nil
+}
+
+// Report the weekly entries of a custom file
+// Diff and balance are calculated from the worked hours, reported values ignored
+func ExportCustomReport(config *Config, global *Common, entries *ListWeekEntry, w io.Writer) error {
+	printfF, printlnF := WriterPrintf(w), WriterPrintln(w)
+
+	var balance Hours = 0
+	if config.InitialBalance != nil {
+		balance += *config.InitialBalance
+	}
+
+	for i, e := range *entries {
+		diff := e.worked - WeekRequiredHours(config, global, e)
+		balance += diff
+
+		_, _ = printlnF("********************")
+		_, _ = printfF("Week: %v (%s) Month: %v Year: %v:\nWorked: %s\nWeek Diff: %s\nBalance: %s\n", i+1, *e.trange, e.month, e.year, *PlusSignIfNecessary(e.worked), *PlusSignIfNecessary(diff), *PlusSignIfNecessary(balance))
+		if e.comment != nil {
+			_, _ = printfF("Comment: %s\n", *e.comment)
+		}
+		_, _ = printlnF("********************")
+		_, _ = printlnF()
+	}
+
+	_, _ = printlnF()
+	_, _ = printfF("Final Balance: %s\n", *PlusSignIfNecessary(balance))
+
+	return nil
 }
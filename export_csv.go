@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// Spreadsheet output of daily entries
+// Weekly summary written into a separate file if enabled in config
+type CsvExporter struct{}
+
+func init() {
+	RegisterExporter("csv", &CsvExporter{})
+}
+
+func (exp *CsvExporter) Files(config *Config, global *Common, data *ExportData) ListExportFile {
+	if data.report == nil {
+		return nil
+	}
+	files := ListExportFile{
+		{ext: "csv", write: func(w io.Writer) error { return ExportReportCsv(config, data.report, w) }},
+	}
+	if config.CsvWeekly {
+		files = append(files, &ExportFile{suffix: AsPtr("weekly"), ext: "csv", write: func(w io.Writer) error {
+			return ExportReportWeeklyCsv(config, data.report, w)
+		}})
+	}
+	return files
+}
+
+// Write one row per day of the report
+// Dates in ISO format, hours use dot decimals regardless of the delimiter
+func ExportReportCsv(config *Config, report *Report, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = config.CsvDelimiter
+
+	_ = cw.Write([]string{"date", "weekday", "worked", "required", "diff", "balance", "day type"})
+	for _, d := range report.days {
+		e := d.entry
+		_ = cw.Write([]string{
+			e.date.Format(ISO_DATE_LAYOUT),
+			e.date.Weekday().String(),
+			e.duration.String(),
+			d.required.String(),
+			d.diff.String(),
+			d.balance.String(),
+			*DayTypeRevMapping[e.dtype],
+		})
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// Write one row per week of the report
+func ExportReportWeeklyCsv(config *Config, report *Report, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = config.CsvDelimiter
+
+	_ = cw.Write([]string{"year", "week", "start", "end", "worked", "required", "diff", "balance"})
+	for _, wk := range report.weeks {
+		_ = cw.Write([]string{
+			strconv.Itoa(wk.year),
+			strconv.Itoa(wk.week),
+			wk.start.Format(ISO_DATE_LAYOUT),
+			wk.end.Format(ISO_DATE_LAYOUT),
+			wk.worked.String(),
+			wk.required.String(),
+			wk.diff.String(),
+			wk.balance.String(),
+		})
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportReportCsv(t *testing.T) {
+	tests := []struct {
+		name   string
+		delim  rune
+		weekly bool
+		want   string
+	}{
+		{
+			name:  "daily",
+			delim: ',',
+			want:  "date,weekday,worked,required,diff,balance,day type\n2023-10-06,Friday,8.00,7.25,0.75,0.75,work\n2023-10-07,Saturday,1.50,0.00,1.50,2.25,work\n",
+		},
+		{
+			name:  "daily semicolon",
+			delim: ';',
+			want:  "date;weekday;worked;required;diff;balance;day type\n2023-10-06;Friday;8.00;7.25;0.75;0.75;work\n2023-10-07;Saturday;1.50;0.00;1.50;2.25;work\n",
+		},
+		{
+			name:   "weekly",
+			delim:  ',',
+			weekly: true,
+			want:   "year,week,start,end,worked,required,diff,balance\n2023,40,2023-10-06,2023-10-07,9.50,7.25,2.25,2.25\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				CsvDelimiter:     tt.delim,
+				DailyHours:       725,
+				ExcludedWeekdays: &ListWeekday{AsPtr(time.Saturday), AsPtr(time.Sunday)},
+			}
+			report := BuildReport(config, reportEntries("06.10.2023", 800, "07.10.2023", 150))
+
+			var sb strings.Builder
+			var err error
+			if tt.weekly {
+				err = ExportReportWeeklyCsv(config, report, &sb)
+			} else {
+				err = ExportReportCsv(config, report, &sb)
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sb.String() != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", sb.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+)
+
+// Balance chart dimensions in pixels
+const (
+	HTML_CHART_WIDTH   = 800
+	HTML_CHART_HEIGHT  = 240
+	HTML_CHART_PADDING = 40
+)
+
+// Template values for the HTML report
+// Hours are preformatted strings
+
+type HtmlWeek struct {
+	Index    int
+	Week     int
+	Range    string
+	Worked   string
+	Required string
+	Diff     string
+	Balance  string
+	Negative bool
+}
+
+type HtmlMonth struct {
+	Name  string
+	Weeks []HtmlWeek
+}
+
+type HtmlChart struct {
+	Width   int
+	Height  int
+	Padding int
+	Points  string
+	ZeroY   string
+	MinY    int
+	MaxY    int
+	MinText string
+	MaxText string
+	From    string
+	To      string
+}
+
+type HtmlReport struct {
+	Title   string
+	Created string
+	Initial string
+	Final   string
+	Months  []HtmlMonth
+	Chart   *HtmlChart
+}
+
+var HTML_REPORT_TEMPLATE = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.8em; text-align: right; }
+th { background: #eee; }
+td.range { text-align: left; }
+tr.month th { background: #dde6f0; text-align: left; }
+tr.negative td { background: #fbe3e3; color: #a00; }
+svg { border: 1px solid #ccc; background: #fafafa; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>Created: {{.Created}}<br>Initial Balance: {{.Initial}}<br>Final Balance: <strong>{{.Final}}</strong></p>
+{{with .Chart}}
+<h2>Balance</h2>
+<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="{{.Height}}" viewBox="0 0 {{.Width}} {{.Height}}">
+<line x1="{{.Padding}}" y1="{{.ZeroY}}" x2="{{.Width}}" y2="{{.ZeroY}}" stroke="#999" stroke-dasharray="4"/>
+<polyline fill="none" stroke="#2a6ebb" stroke-width="2" points="{{.Points}}"/>
+<text x="2" y="{{.MaxY}}" font-size="11">{{.MaxText}}</text>
+<text x="2" y="{{.MinY}}" font-size="11">{{.MinText}}</text>
+<text x="{{.Padding}}" y="{{.Height}}" font-size="11" dy="-4">{{.From}}</text>
+<text x="{{.Width}}" y="{{.Height}}" font-size="11" dy="-4" text-anchor="end">{{.To}}</text>
+</svg>
+{{end}}
+<h2>Weeks</h2>
+<table>
+<tr><th>Week</th><th>Dates</th><th>Worked</th><th>Required</th><th>Diff</th><th>Balance</th></tr>
+{{range .Months}}
+<tr class="month"><th colspan="6">{{.Name}}</th></tr>
+{{range .Weeks}}
+<tr{{if .Negative}} class="negative"{{end}}><td>{{.Index}} ({{.Week}})</td><td class="range">{{.Range}}</td><td>{{.Worked}}</td><td>{{.Required}}</td><td>{{.Diff}}</td><td>{{.Balance}}</td></tr>
+{{end}}
+{{end}}
+</table>
+</body>
+</html>
+`))
+
+// Browser viewable report of daily entries
+type HtmlExporter struct{}
+
+func init() {
+	RegisterExporter("html", &HtmlExporter{})
+}
+
+func (exp *HtmlExporter) Files(config *Config, global *Common, data *ExportData) ListExportFile {
+	if data.report == nil {
+		return nil
+	}
+	return ListExportFile{
+		{ext: "html", write: func(w io.Writer) error { return ExportReportHtml(config, data.report, w) }},
+	}
+}
+
+// Line chart of the running balance after each day
+// Y axis always includes zero
+func newHtmlChart(config *Config, report *Report) *HtmlChart {
+	if len(report.days) <= 0 {
+		return nil
+	}
+
+	chart := &HtmlChart{
+		Width:   HTML_CHART_WIDTH,
+		Height:  HTML_CHART_HEIGHT,
+		Padding: HTML_CHART_PADDING,
+		MinY:    HTML_CHART_HEIGHT - HTML_CHART_PADDING,
+		MaxY:    HTML_CHART_PADDING / 2,
+	}
+
+	var minB, maxB Hours = 0, 0
+	for _, d := range report.days {
+		minB = min(minB, d.balance)
+		maxB = max(maxB, d.balance)
+	}
+	span := maxB - minB
+	if span == 0 {
+		span = HOURS_SCALE
+	}
+
+	first := report.days[0].entry.date
+	last := report.days[len(report.days)-1].entry.date
+	days := last.Sub(first).Hours() / 24
+	if days <= 0 {
+		days = 1
+	}
+
+	plotW := float64(HTML_CHART_WIDTH - HTML_CHART_PADDING)
+	plotH := float64(chart.MinY - chart.MaxY)
+	y := func(b Hours) float64 {
+		return float64(chart.MinY) - float64(b-minB)/float64(span)*plotH
+	}
+
+	points := make([]string, 0, len(report.days))
+	for _, d := range report.days {
+		x := float64(HTML_CHART_PADDING) + d.entry.date.Sub(first).Hours()/24/days*plotW
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x, y(d.balance)))
+	}
+
+	chart.Points = strings.Join(points, " ")
+	chart.ZeroY = fmt.Sprintf("%.1f", y(0))
+	chart.MinText = *PlusSignIfNecessary(minB)
+	chart.MaxText = *PlusSignIfNecessary(maxB)
+	chart.From = first.Format(*config.DateParseLayout)
+	chart.To = last.Format(*config.DateParseLayout)
+
+	return chart
+}
+
+// Single offline HTML file with weekly table grouped by month and balance chart
+// Weeks are grouped by the month of their first day
+func ExportReportHtml(config *Config, report *Report, w io.Writer) error {
+	out := HtmlReport{
+		Title:   "Hour Balance Report",
+		Created: time.Now().Format(*config.DateParseLayout),
+		Initial: *PlusSignIfNecessary(report.initial),
+		Final:   *PlusSignIfNecessary(report.balance),
+		Months:  make([]HtmlMonth, 0),
+		Chart:   newHtmlChart(config, report),
+	}
+
+	var month *HtmlMonth
+	for _, wk := range report.weeks {
+		name := wk.start.Format("2006-01")
+		if month == nil || month.Name != name {
+			out.Months = append(out.Months, HtmlMonth{Name: name})
+			month = &out.Months[len(out.Months)-1]
+		}
+		month.Weeks = append(month.Weeks, HtmlWeek{
+			Index:    wk.index,
+			Week:     wk.week,
+			Range:    wk.start.Format(*config.DateParseLayout) + " - " + wk.end.Format(*config.DateParseLayout),
+			Worked:   wk.worked.String(),
+			Required: wk.required.String(),
+			Diff:     *PlusSignIfNecessary(wk.diff),
+			Balance:  *PlusSignIfNecessary(wk.balance),
+			Negative: wk.diff < 0,
+		})
+	}
+
+	return HTML_REPORT_TEMPLATE.Execute(w, out)
+}
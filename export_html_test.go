@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportReportHtml(t *testing.T) {
+	tests := []struct {
+		name     string
+		entries  *ListSingleEntry
+		contains []string
+		missing  []string
+	}{
+		{
+			name:     "weeks grouped by month",
+			entries:  reportEntries("26.10.2023", 725, "30.10.2023", 625, "06.11.2023", 825),
+			contains: []string{"<th colspan=\"6\">2023-10</th>", "<th colspan=\"6\">2023-11</th>", "<polyline", "Final Balance: <strong>&#43;0.00</strong>"},
+		},
+		{
+			name:     "negative week highlighted",
+			entries:  reportEntries("30.10.2023", 625),
+			contains: []string{"<tr class=\"negative\"><td>1 (44)</td><td class=\"range\">30.10.2023 - 30.10.2023</td>"},
+		},
+		{
+			name:     "no days, no chart",
+			entries:  reportEntries(),
+			contains: []string{"Final Balance: <strong>&#43;0.00</strong>"},
+			missing:  []string{"<svg"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				DailyHours:       725,
+				DateParseLayout:  AsPtr("02.01.2006"),
+				ExcludedWeekdays: &ListWeekday{AsPtr(time.Saturday), AsPtr(time.Sunday)},
+			}
+
+			var sb strings.Builder
+			if err := ExportReportHtml(config, BuildReport(config, tt.entries), &sb); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(sb.String(), s) {
+					t.Errorf("output missing %q", s)
+				}
+			}
+			for _, s := range tt.missing {
+				if strings.Contains(sb.String(), s) {
+					t.Errorf("output contains %q", s)
+				}
+			}
+		})
+	}
+}
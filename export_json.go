@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Exported representations of the report for JSON output
+// Dates are in ISO_DATE_LAYOUT
+
+type JsonReportDay struct {
+	Date     string  `json:"date"`
+	Weekday  string  `json:"weekday"`
+	DayType  string  `json:"day_type"`
+	Holiday  *string `json:"holiday,omitempty"`
+	Worked   Hours   `json:"worked"`
+	Required Hours   `json:"required"`
+	Diff     Hours   `json:"diff"`
+	Balance  Hours   `json:"balance"`
+}
+
+type JsonReportWeek struct {
+	Index    int     `json:"index"`
+	Year     int     `json:"year,omitempty"`
+	Week     int     `json:"week,omitempty"`
+	Start    string  `json:"start,omitempty"`
+	End      string  `json:"end,omitempty"`
+	Comment  *string `json:"comment,omitempty"`
+	Worked   Hours   `json:"worked"`
+	Required Hours   `json:"required"`
+	Diff     Hours   `json:"diff"`
+	Balance  Hours   `json:"balance"`
+}
+
+type JsonReport struct {
+	InitialBalance Hours            `json:"initial_balance"`
+	Days           []JsonReportDay  `json:"days,omitempty"`
+	Weeks          []JsonReportWeek `json:"weeks"`
+	FinalBalance   Hours            `json:"final_balance"`
+}
+
+type JsonCheckRow struct {
+	Index           int      `json:"index"`
+	Range           string   `json:"range"`
+	Year            Year     `json:"year"`
+	Month           Month    `json:"month"`
+	Worked          Hours    `json:"worked"`
+	Required        Hours    `json:"required"`
+	ReportedDiff    Hours    `json:"reported_diff"`
+	ExpectedDiff    Hours    `json:"expected_diff"`
+	ReportedBalance Hours    `json:"reported_balance"`
+	ExpectedBalance Hours    `json:"expected_balance"`
+	Errors          []string `json:"errors"`
+}
+
+type JsonCheckResult struct {
+	Rows           []JsonCheckRow `json:"rows"`
+	EntriesChecked int            `json:"entries_checked"`
+	ErrorsFound    int            `json:"errors_found"`
+	DivergedAt     *int           `json:"diverged_at,omitempty"`
+	FinalBalance   Hours          `json:"final_balance"`
+}
+
+// Machine readable output of any results
+type JsonExporter struct{}
+
+func init() {
+	RegisterExporter("json", &JsonExporter{})
+}
+
+func (exp *JsonExporter) Files(config *Config, global *Common, data *ExportData) ListExportFile {
+	file := &ExportFile{ext: "json"}
+	switch {
+	case data.report != nil:
+		file.write = func(w io.Writer) error { return ExportReportJson(config, data.report, w) }
+	case data.weeks != nil:
+		file.write = func(w io.Writer) error { return ExportCustomReportJson(config, global, data.weeks, w) }
+	case data.check != nil:
+		file.suffix = AsPtr("check")
+		file.write = func(w io.Writer) error { return ExportCheckJson(data.check, w) }
+	default:
+		return nil
+	}
+	return ListExportFile{file}
+}
+
+func writeJson(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func initialBalance(config *Config) Hours {
+	if config.InitialBalance != nil {
+		return *config.InitialBalance
+	}
+	return 0
+}
+
+// Write the days and weeks of the report
+func ExportReportJson(config *Config, report *Report, w io.Writer) error {
+	out := JsonReport{
+		InitialBalance: report.initial,
+		Days:           make([]JsonReportDay, 0, len(report.days)),
+		Weeks:          make([]JsonReportWeek, 0, len(report.weeks)),
+		FinalBalance:   report.balance,
+	}
+
+	for _, d := range report.days {
+		e := d.entry
+		day := JsonReportDay{
+			Date:     e.date.Format(ISO_DATE_LAYOUT),
+			Weekday:  e.date.Weekday().String(),
+			DayType:  *DayTypeRevMapping[e.dtype],
+			Worked:   e.duration,
+			Required: d.required,
+			Diff:     d.diff,
+			Balance:  d.balance,
+		}
+		if d.holiday != nil {
+			day.Holiday = d.holiday.name
+		}
+		out.Days = append(out.Days, day)
+	}
+
+	for _, wk := range report.weeks {
+		out.Weeks = append(out.Weeks, JsonReportWeek{
+			Index:    wk.index,
+			Year:     wk.year,
+			Week:     wk.week,
+			Start:    wk.start.Format(ISO_DATE_LAYOUT),
+			End:      wk.end.Format(ISO_DATE_LAYOUT),
+			Worked:   wk.worked,
+			Required: wk.required,
+			Diff:     wk.diff,
+			Balance:  wk.balance,
+		})
+	}
+
+	return writeJson(w, out)
+}
+
+// Write the weekly entries of a custom file
+// Diff and balance are calculated from the worked hours, reported values ignored
+func ExportCustomReportJson(config *Config, global *Common, entries *ListWeekEntry, w io.Writer) error {
+	out := JsonReport{
+		InitialBalance: initialBalance(config),
+		Weeks:          make([]JsonReportWeek, 0, len(*entries)),
+	}
+
+	balance := out.InitialBalance
+	for i, e := range *entries {
+		required := WeekRequiredHours(config, global, e)
+		diff := e.worked - required
+		balance += diff
+
+		week := JsonReportWeek{
+			Index:    i + 1,
+			Comment:  e.comment,
+			Worked:   e.worked,
+			Required: required,
+			Diff:     diff,
+			Balance:  balance,
+		}
+		// Dates unknown without month header
+		if !e.start.IsZero() {
+			week.Start = e.start.Format(ISO_DATE_LAYOUT)
+			week.End = e.end.Format(ISO_DATE_LAYOUT)
+		}
+		out.Weeks = append(out.Weeks, week)
+	}
+	out.FinalBalance = balance
+
+	return writeJson(w, out)
+}
+
+// Write the reported and expected values of each checked entry
+func ExportCheckJson(result *CheckResult, w io.Writer) error {
+	out := JsonCheckResult{
+		Rows:           make([]JsonCheckRow, 0, len(result.rows)),
+		EntriesChecked: len(result.rows),
+		ErrorsFound:    result.errorCount,
+		FinalBalance:   result.balance,
+	}
+
+	for _, row := range result.rows {
+		e := row.entry
+		jrow := JsonCheckRow{
+			Index:           row.index,
+			Range:           *e.trange,
+			Year:            e.year,
+			Month:           e.month,
+			Worked:          e.worked,
+			Required:        row.required,
+			ReportedDiff:    e.diff,
+			ExpectedDiff:    row.expectedDiff,
+			ReportedBalance: e.balance,
+			ExpectedBalance: row.expectedBalance,
+			Errors:          make([]string, 0, len(row.errors)),
+		}
+		for _, msg := range row.errors {
+			jrow.Errors = append(jrow.Errors, *msg)
+		}
+		out.Rows = append(out.Rows, jrow)
+	}
+
+	if result.divergence != nil {
+		out.DivergedAt = AsPtr(result.divergence.index)
+	}
+
+	return writeJson(w, out)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportCustomReportJson(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse("02.01.2006", s)
+		return d
+	}
+
+	tests := []struct {
+		name    string
+		entry   *WeekEntry
+		start   string
+		balance float64
+	}{
+		{
+			name:    "dates known",
+			entry:   &WeekEntry{trange: AsPtr("4.10.-8.10."), start: date("04.10.2023"), end: date("08.10.2023"), worked: 2275},
+			start:   "2023-10-04",
+			balance: 2.5,
+		},
+		{
+			name:    "dates unknown",
+			entry:   &WeekEntry{trange: AsPtr("4.10.-8.10."), worked: 3725},
+			balance: 2.5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				DailyHours:       725,
+				ExcludedWeekdays: &ListWeekday{AsPtr(time.Saturday), AsPtr(time.Sunday)},
+				InitialBalance:   AsPtr[Hours](150),
+			}
+			global := &Common{weeklyHours: 3625}
+
+			var sb strings.Builder
+			if err := ExportCustomReportJson(config, global, &ListWeekEntry{tt.entry}, &sb); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var out struct {
+				Weeks []map[string]any `json:"weeks"`
+				Final float64          `json:"final_balance"`
+			}
+			if err := json.Unmarshal([]byte(sb.String()), &out); err != nil {
+				t.Fatalf("invalid json: %v\n%s", err, sb.String())
+			}
+			if len(out.Weeks) != 1 {
+				t.Fatalf("got %v weeks, want 1", len(out.Weeks))
+			}
+			start, ok := out.Weeks[0]["start"]
+			if tt.start == "" && ok {
+				t.Errorf("got start %v, want omitted", start)
+			}
+			if tt.start != "" && start != tt.start {
+				t.Errorf("got start %v, want %s", start, tt.start)
+			}
+			if out.Final != tt.balance {
+				t.Errorf("got final balance %v, want %v", out.Final, tt.balance)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"io"
+	"time"
+)
+
+// Markdown output of daily entries
+type MarkdownExporter struct{}
+
+func init() {
+	RegisterExporter("markdown", &MarkdownExporter{})
+}
+
+func (exp *MarkdownExporter) Files(config *Config, global *Common, data *ExportData) ListExportFile {
+	if data.report == nil {
+		return nil
+	}
+	return ListExportFile{
+		{ext: "md", write: func(w io.Writer) error { return ExportReportMarkdown(config, data.report, w) }},
+	}
+}
+
+// Totals of the days within a calendar month
+type MarkdownMonth struct {
+	start   time.Time
+	end     time.Time
+	worked  Hours
+	diff    Hours
+	balance Hours
+}
+
+// Weekly and monthly tables for wikis and merge requests
+// Months are summed from days, weeks may span two months
+func ExportReportMarkdown(config *Config, report *Report, w io.Writer) error {
+	printfF := WriterPrintf(w)
+	layout := *config.DateParseLayout
+
+	_, _ = printfF("# Hour Balance Report\n\n")
+	_, _ = printfF("Initial balance: %s\n\n", *PlusSignIfNecessary(report.initial))
+
+	_, _ = printfF("## Weeks\n\n")
+	_, _ = printfF("| Week | Dates | Worked | Diff | Balance |\n")
+	_, _ = printfF("| ---: | :--- | ---: | ---: | ---: |\n")
+	for _, wk := range report.weeks {
+		_, _ = printfF("| %d (%d) | %s - %s | %s | %s | %s |\n", wk.index, wk.week, wk.start.Format(layout), wk.end.Format(layout), wk.worked, *PlusSignIfNecessary(wk.diff), *PlusSignIfNecessary(wk.balance))
+	}
+
+	_, _ = printfF("\n## Months\n\n")
+	_, _ = printfF("| Month | Dates | Worked | Diff | Balance |\n")
+	_, _ = printfF("| :--- | :--- | ---: | ---: | ---: |\n")
+	var month *MarkdownMonth
+	printMonth := func() {
+		_, _ = printfF("| %s | %s - %s | %s | %s | %s |\n", month.start.Format("2006-01"), month.start.Format(layout), month.end.Format(layout), month.worked, *PlusSignIfNecessary(month.diff), *PlusSignIfNecessary(month.balance))
+	}
+	for _, d := range report.days {
+		e := d.entry
+		if month != nil && (month.start.Year() != e.date.Year() || month.start.Month() != e.date.Month()) {
+			printMonth()
+			month = nil
+		}
+		if month == nil {
+			month = &MarkdownMonth{start: e.date}
+		}
+		month.end = e.date
+		month.worked += e.duration
+		month.diff += d.diff
+		month.balance = d.balance
+	}
+	if month != nil {
+		printMonth()
+	}
+
+	_, err := printfF("\n**Final balance: %s**\n", *PlusSignIfNecessary(report.balance))
+	return err
+}
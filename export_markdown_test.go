@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportReportMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		entries  *ListSingleEntry
+		contains []string
+	}{
+		{
+			name:    "week spanning two months",
+			entries: reportEntries("30.10.2023", 825, "31.10.2023", 725, "01.11.2023", 625),
+			contains: []string{
+				"| 1 (44) | 30.10.2023 - 01.11.2023 | 21.75 | +0.00 | +0.00 |",
+				"| 2023-10 | 30.10.2023 - 31.10.2023 | 15.50 | +1.00 | +1.00 |",
+				"| 2023-11 | 01.11.2023 - 01.11.2023 | 6.25 | -1.00 | +0.00 |",
+				"**Final balance: +0.00**",
+			},
+		},
+		{
+			name:     "no days",
+			entries:  reportEntries(),
+			contains: []string{"Initial balance: +0.00", "**Final balance: +0.00**"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				DailyHours:       725,
+				DateParseLayout:  AsPtr("02.01.2006"),
+				ExcludedWeekdays: &ListWeekday{AsPtr(time.Saturday), AsPtr(time.Sunday)},
+			}
+
+			var sb strings.Builder
+			if err := ExportReportMarkdown(config, BuildReport(config, tt.entries), &sb); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(sb.String(), s) {
+					t.Errorf("output missing %q:\n%s", s, sb.String())
+				}
+			}
+		})
+	}
+}
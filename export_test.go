@@ -0,0 +1,135 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckCustomEntries(t *testing.T) {
+	week := func(trange string, month Month, worked, diff, balance Hours) *WeekEntry {
+		return &WeekEntry{trange: AsPtr(trange), year: 2023, month: month, worked: worked, diff: diff, balance: balance}
+	}
+
+	tests := []struct {
+		name       string
+		entries    ListWeekEntry
+		errors     int
+		divergence int
+		balance    Hours
+	}{
+		{
+			name: "all correct",
+			entries: ListWeekEntry{
+				week("2.10.-8.10.", 10, 4125, 500, 500),
+				week("9.10.-15.10.", 10, 3500, -125, 375),
+			},
+			divergence: -1,
+			balance:    375,
+		},
+		{
+			name: "single balance mismatch reported once",
+			entries: ListWeekEntry{
+				week("2.10.-8.10.", 10, 4125, 500, 600),
+				week("9.10.-15.10.", 10, 3500, -125, 475),
+				week("16.10.-22.10.", 10, 3625, 0, 475),
+			},
+			errors:     1,
+			divergence: 0,
+			balance:    375,
+		},
+		{
+			name: "wrong diff",
+			entries: ListWeekEntry{
+				week("2.10.-8.10.", 10, 4125, 400, 400),
+			},
+			errors:     2,
+			divergence: 0,
+			balance:    500,
+		},
+		{
+			name: "skipped month",
+			entries: ListWeekEntry{
+				week("2.10.-8.10.", 10, 3625, 0, 0),
+				week("4.12.-10.12.", 12, 3625, 0, 0),
+			},
+			errors:     1,
+			divergence: -1,
+			balance:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{DailyHours: 725}
+			global := &Common{weeklyHours: 3625}
+
+			result := CheckCustomEntries(config, global, &tt.entries)
+			if result.errorCount != tt.errors {
+				t.Errorf("got %v errors, want %v", result.errorCount, tt.errors)
+			}
+			if tt.divergence < 0 && result.divergence != nil {
+				t.Errorf("got divergence at %v, want none", result.divergence.index)
+			}
+			if tt.divergence >= 0 && (result.divergence == nil || result.divergence.index != tt.divergence) {
+				t.Errorf("got divergence %v, want at %v", result.divergence, tt.divergence)
+			}
+			if result.balance != tt.balance {
+				t.Errorf("got balance %v, want %v", result.balance, tt.balance)
+			}
+		})
+	}
+}
+
+func TestExporterFiles(t *testing.T) {
+	data := map[string]*ExportData{
+		"report": {report: &Report{}},
+		"weeks":  {weeks: &ListWeekEntry{}},
+		"check":  {check: &CheckResult{}},
+	}
+
+	tests := []struct {
+		exporter string
+		data     string
+		exts     []string
+	}{
+		{exporter: "text", data: "report", exts: []string{"txt"}},
+		{exporter: "text", data: "weeks", exts: []string{"txt"}},
+		{exporter: "text", data: "check", exts: []string{"txt"}},
+		{exporter: "json", data: "check", exts: []string{"json"}},
+		{exporter: "csv", data: "report", exts: []string{"csv"}},
+		{exporter: "csv", data: "weeks"},
+		{exporter: "html", data: "check"},
+		{exporter: "markdown", data: "report", exts: []string{"md"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.exporter+" "+tt.data, func(t *testing.T) {
+			exporter, err := ParseExporter(&tt.exporter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			files := exporter.Files(&Config{}, &Common{}, data[tt.data])
+			if len(files) != len(tt.exts) {
+				t.Fatalf("got %v files, want %v", len(files), len(tt.exts))
+			}
+			for i, ext := range tt.exts {
+				if files[i].ext != ext {
+					t.Errorf("file %v: got ext %s, want %s", i, files[i].ext, ext)
+				}
+			}
+		})
+	}
+}
+
+// Report range without days prints only the final balance
+func TestExportClockifyReportEmpty(t *testing.T) {
+	var sb strings.Builder
+	report := &Report{days: ListReportDay{}, weeks: ListReportWeek{}, initial: 150, balance: 150}
+
+	if err := ExportClockifyReport(&Config{}, &Common{}, report, &sb); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out := sb.String(); strings.Contains(out, "Week:") || !strings.Contains(out, "Final Balance: +1.50") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
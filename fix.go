@@ -0,0 +1,191 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Number of unchanged lines around changes in unified diff
+const DIFF_CONTEXT_LINES = 3
+
+// Format hours like the original value in the custom file
+// Keeps the decimal separator and at least as many decimals as the original
+func FormatHoursLike(val Hours, orig *string) string {
+	sep := "."
+	if strings.Contains(*orig, ",") {
+		sep = ","
+	}
+	minDecimals := 0
+	if i := strings.IndexAny(*orig, ",."); i >= 0 {
+		minDecimals = len(strings.TrimRight((*orig)[i+1:], ")"))
+	}
+
+	str := strings.TrimPrefix(val.String(), "-")
+	whole, frac, _ := strings.Cut(str, ".")
+	for len(frac) > minDecimals && strings.HasSuffix(frac, "0") {
+		frac = strings.TrimSuffix(frac, "0")
+	}
+
+	str = "+" + whole
+	if val < 0 {
+		str = "-" + whole
+	}
+	if frac != "" {
+		str += sep + frac
+	}
+	return str
+}
+
+// Unified diff of line replacements
+// Both inputs have equal line count, lines are only replaced in place
+func UnifiedDiff(nameA *string, nameB *string, a []string, b []string) string {
+	var sb strings.Builder
+
+	changed := make([]int, 0)
+	for i := range a {
+		if a[i] != b[i] {
+			changed = append(changed, i)
+		}
+	}
+	if len(changed) <= 0 {
+		return ""
+	}
+
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", *nameA, *nameB)
+
+	for i := 0; i < len(changed); {
+		// Merge changes close enough to share context
+		j := i
+		for j+1 < len(changed) && changed[j+1]-changed[j] <= 2*DIFF_CONTEXT_LINES {
+			j++
+		}
+		start := max(0, changed[i]-DIFF_CONTEXT_LINES)
+		end := min(len(a), changed[j]+DIFF_CONTEXT_LINES+1)
+
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", start+1, end-start, start+1, end-start)
+		for k := start; k < end; {
+			if a[k] == b[k] {
+				fmt.Fprintf(&sb, " %s\n", a[k])
+				k++
+				continue
+			}
+			// Changed block, removed lines first
+			l := k
+			for l < end && a[l] != b[l] {
+				l++
+			}
+			for m := k; m < l; m++ {
+				fmt.Fprintf(&sb, "-%s\n", a[m])
+			}
+			for m := k; m < l; m++ {
+				fmt.Fprintf(&sb, "+%s\n", b[m])
+			}
+			k = l
+		}
+		i = j + 1
+	}
+
+	return sb.String()
+}
+
+// Recalculate diff and balance of each custom file entry
+// Writes corrected copy of the file and unified diff of the changes
+// Original layout, month headers and comments are preserved
+func FixCustomFile(config *Config, global *Common, entries *ListWeekEntry) error {
+	result := CheckCustomEntries(config, global, entries)
+
+	content, err := os.ReadFile(*config.ImportFilePath)
+	if err != nil {
+		fmt.Printf("ERROR: Failed to read import file (path: %s) Err: %s\n", *config.ImportFilePath, err.Error())
+		return err
+	}
+
+	orig := strings.Split(string(content), "\n")
+	fixed := slices.Clone(orig)
+
+	// Replacements per line, applied from right to left
+	type replacement struct {
+		pos *FieldPos
+		val string
+	}
+	replacements := map[Line][]replacement{}
+
+	unfixable := 0
+	for _, row := range result.rows {
+		e := row.entry
+		if e.diffPos == nil || e.balancePos == nil {
+			fmt.Printf("ERROR: Entry %v (%s): Value positions unknown, cannot fix.\n", row.index, *e.trange)
+			return errors.New("value positions unknown")
+		}
+		if e.diff != row.expectedDiff {
+			old := orig[e.diffPos.line-1][e.diffPos.start:e.diffPos.end]
+			replacements[e.diffPos.line] = append(replacements[e.diffPos.line], replacement{e.diffPos, FormatHoursLike(row.expectedDiff, &old)})
+		}
+		if e.balance != row.recalculatedBalance {
+			old := orig[e.balancePos.line-1][e.balancePos.start:e.balancePos.end]
+			replacements[e.balancePos.line] = append(replacements[e.balancePos.line], replacement{e.balancePos, "(" + FormatHoursLike(row.recalculatedBalance, &old) + ")"})
+		}
+		// Continuity errors need manual fixing
+		if row.continuityError {
+			fmt.Printf("WARNING: Entry %v (%s): Year-month continuity error cannot be fixed automatically.\n", row.index, *e.trange)
+			unfixable++
+		}
+	}
+
+	if len(replacements) <= 0 {
+		fmt.Println("No corrections needed, diffs and balances are correct.")
+		if unfixable > 0 {
+			return errors.New("unfixable discrepancies found")
+		}
+		return nil
+	}
+
+	for line, reps := range replacements {
+		slices.SortFunc(reps, func(a, b replacement) int { return b.pos.start - a.pos.start })
+		str := fixed[line-1]
+		for _, r := range reps {
+			str = str[:r.pos.start] + r.val + str[r.pos.end:]
+		}
+		fixed[line-1] = str
+	}
+
+	// Corrected copy into export dir, or next to the import file
+	dir := filepath.Dir(*config.ImportFilePath)
+	if config.ExportDir != nil {
+		dir = *config.ExportDir
+	}
+	ext := filepath.Ext(*config.ImportFileName)
+	base := strings.TrimSuffix(*config.ImportFileName, ext)
+	fixedName := base + ".fixed" + ext
+	fixedPath := filepath.Join(dir, fixedName)
+	diffPath := filepath.Join(dir, base+".fixed.diff")
+
+	diff := UnifiedDiff(config.ImportFileName, &fixedName, orig, fixed)
+
+	if err = os.WriteFile(fixedPath, []byte(strings.Join(fixed, "\n")), 0644); err != nil {
+		fmt.Printf("ERROR: Could not write corrected file: %s Error: %s\n", fixedPath, err.Error())
+		return err
+	}
+	if err = os.WriteFile(diffPath, []byte(diff), 0644); err != nil {
+		fmt.Printf("ERROR: Could not write diff file: %s Error: %s\n", diffPath, err.Error())
+		return err
+	}
+
+	fmt.Println("Changes:")
+	fmt.Println()
+	fmt.Print(diff)
+	fmt.Println()
+	fmt.Printf("Corrected %v line(s). Final Balance: %s\n", len(replacements), *PlusSignIfNecessary(result.balance))
+	fmt.Println("Corrected file written into:", fixedPath)
+	fmt.Println("Diff written into:", diffPath)
+
+	if unfixable > 0 {
+		return errors.New("unfixable discrepancies found")
+	}
+
+	return nil
+}
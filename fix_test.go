@@ -0,0 +1,70 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestFormatHoursLike(t *testing.T) {
+	tests := []struct {
+		val  Hours
+		orig string
+		want string
+	}{
+		{val: 500, orig: "+5,0", want: "+5,0"},
+		{val: 525, orig: "+5,0", want: "+5,25"},
+		{val: -150, orig: "-2", want: "-1.5"},
+		{val: 700, orig: "(+6.50)", want: "+7.00"},
+		{val: 0, orig: "+1", want: "+0"},
+		{val: -75, orig: "+0,75", want: "-0,75"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.orig, func(t *testing.T) {
+			if got := FormatHoursLike(tt.val, &tt.orig); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a    []string
+		b    []string
+		want string
+	}{
+		{
+			name: "no changes",
+			a:    []string{"a", "b"},
+			b:    []string{"a", "b"},
+			want: "",
+		},
+		{
+			name: "single change with context",
+			a:    []string{"1", "2", "3", "4", "5", "6", "7", "8", "9"},
+			b:    []string{"1", "2", "3", "4", "X", "6", "7", "8", "9"},
+			want: "--- a\n+++ b\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+X\n 6\n 7\n 8\n",
+		},
+		{
+			name: "adjacent changes in one block",
+			a:    []string{"1", "2", "3"},
+			b:    []string{"X", "Y", "3"},
+			want: "--- a\n+++ b\n@@ -1,3 +1,3 @@\n-1\n-2\n+X\n+Y\n 3\n",
+		},
+		{
+			name: "distant changes in separate hunks",
+			a:    []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"},
+			b:    []string{"X", "2", "3", "4", "5", "6", "7", "8", "9", "Y"},
+			want: "--- a\n+++ b\n@@ -1,4 +1,4 @@\n-1\n+X\n 2\n 3\n 4\n@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+Y\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnifiedDiff(AsPtr("a"), AsPtr("b"), tt.a, tt.b); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Aggregate single days into weekly custom file entries
+// Weeks run from monday to sunday, the first week starts from the first day
+// Required hours are counted the same way as in check mode, so the output checks clean
+func AggregateWeeks(config *Config, entries *ListSingleEntry) *ListWeekEntry {
+	arr := AsPtr(make(ListWeekEntry, 0, len(*entries)/5+1))
+
+	if len(*entries) <= 0 {
+		return arr
+	}
+
+	days := map[time.Time]*SingleEntry{}
+	for _, e := range *entries {
+		days[DateKey(e.date)] = e
+	}
+
+	var balance Hours = 0
+	if config.InitialBalance != nil {
+		balance += *config.InitialBalance
+	}
+
+	first := DateKey((*entries)[0].date)
+	last := DateKey((*entries)[len(*entries)-1].date)
+
+	// Monday of the first week, ISO weeks start on monday
+	monday := first.AddDate(0, 0, -((int(first.Weekday()) + 6) % 7))
+
+	for ; !monday.After(last); monday = monday.AddDate(0, 0, 7) {
+		week := &WeekEntry{
+			start:    monday,
+			end:      monday.AddDate(0, 0, 6),
+			dayTypes: DayTypeDateMap{},
+		}
+		if week.start.Before(first) {
+			week.start = first
+		}
+
+		var required Hours = 0
+		for d := week.start; !d.After(week.end); d = d.AddDate(0, 0, 1) {
+			dtype := LookupDayType(config, d, WORK_DAY)
+			if e, ok := days[d]; ok {
+				week.worked += e.duration
+				// Types not derivable from config are annotated into comment
+				if e.dtype != dtype {
+					week.dayTypes[d] = e.dtype
+					dtype = e.dtype
+				}
+			}
+			required += DayRequiredHours(config, d, dtype)
+		}
+
+		week.diff = week.worked - required
+		balance += week.diff
+		week.balance = balance
+
+		week.year = (Year)(week.start.Year())
+		week.month = (Month)(week.start.Month())
+		week.trange = AsPtr(fmt.Sprintf("%d.%d.-%d.%d.", week.start.Day(), week.start.Month(), week.end.Day(), week.end.Month()))
+		week.comment = FormatCommentDayTypes(week.dayTypes)
+
+		*arr = append(*arr, week)
+	}
+
+	return arr
+}
+
+// Day type annotation for custom file comment, e.g. VACATION 3.10. 4.10. SICK 6.10.
+// Returns nil if no annotated days
+func FormatCommentDayTypes(dayTypes DayTypeDateMap) *string {
+	if len(dayTypes) <= 0 {
+		return nil
+	}
+
+	dates := make([]time.Time, 0, len(dayTypes))
+	for d := range dayTypes {
+		dates = append(dates, d)
+	}
+	slices.SortFunc(dates, func(a, b time.Time) int { return a.Compare(b) })
+
+	parts := make([]string, 0)
+	for dtype := WORK_DAY; int(dtype) < len(DayTypeRevMapping); dtype++ {
+		var sb strings.Builder
+		for _, d := range dates {
+			if dayTypes[d] == dtype {
+				fmt.Fprintf(&sb, " %d.%d.", d.Day(), d.Month())
+			}
+		}
+		if sb.Len() > 0 {
+			parts = append(parts, strings.ToUpper(*DayTypeRevMapping[dtype])+sb.String())
+		}
+	}
+
+	return AsPtr(strings.Join(parts, " "))
+}
+
+// Write weekly entries in the custom file format read by check mode
+func ExportCustomFormat(entries *ListWeekEntry, w io.Writer) error {
+	printfF, printlnF := WriterPrintf(w), WriterPrintln(w)
+
+	var prevYear Year
+	var prevMonth Month
+
+	for _, e := range *entries {
+		// Month header whenever the month of week start changes
+		if e.year != prevYear || e.month != prevMonth {
+			_, _ = printlnF("--------------------")
+			_, _ = printfF("%04d-%02d\n", e.year, e.month)
+			_, _ = printlnF("--------------------")
+			prevYear = e.year
+			prevMonth = e.month
+		}
+
+		_, _ = printlnF(*e.trange)
+		if e.comment != nil {
+			_, _ = printlnF(*e.comment)
+		}
+		_, _ = printlnF(e.worked.StringFi(false))
+		_, _ = printlnF(e.diff.StringFi(true))
+		_, _ = printfF("(%s)\n", e.balance.StringFi(true))
+		_, _ = printlnF()
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatCommentDayTypes(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse("02.01.2006", s)
+		return d
+	}
+
+	tests := []struct {
+		name     string
+		dayTypes DayTypeDateMap
+		want     *string
+	}{
+		{name: "none", dayTypes: DayTypeDateMap{}, want: nil},
+		{
+			name:     "sorted by type and date",
+			dayTypes: DayTypeDateMap{date("06.10.2023"): SICK_DAY, date("04.10.2023"): VACATION_DAY, date("03.10.2023"): VACATION_DAY},
+			want:     AsPtr("VACATION 3.10. 4.10. SICK 6.10."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatCommentDayTypes(tt.dayTypes)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Generated custom file has to pass check mode without errors
+func TestGenerateCheckRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		rows  [][4]string
+		weeks int
+	}{
+		{
+			name: "starts mid-week",
+			rows: [][4]string{
+				{"Development", "", "10.10.2023", "7.00"},
+				{"Development", "", "09.10.2023", "8.00"},
+				{"Development", "", "06.10.2023", "7.25"},
+				{"Development", "", "05.10.2023", "7.25"},
+				{"Development", "", "04.10.2023", "7.50"},
+			},
+			weeks: 2,
+		},
+		{
+			name: "absence and holiday",
+			rows: [][4]string{
+				{"Development", "", "08.12.2023", "7.25"},
+				{"Vacation", "", "07.12.2023", "7.25"},
+				{"Development", "", "05.12.2023", "7.25"},
+			},
+			weeks: 1,
+		},
+		{
+			name: "crosses month and year",
+			rows: [][4]string{
+				{"Development", "", "02.01.2024", "7.25"},
+				{"Development", "", "29.12.2023", "6.00"},
+				{"Development", "", "27.12.2023", "7.25"},
+			},
+			weeks: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				ClockifyColumns:  NewClockifyColumnAliases(),
+				CsvDelimiter:     ',',
+				DateParseLayout:  AsPtr("02.01.2006"),
+				DailyHours:       725,
+				ExcludedWeekdays: &ListWeekday{AsPtr(time.Saturday), AsPtr(time.Sunday)},
+				Holidays:         NewHolidayCalendar(FinnishHolidays),
+				InitialBalance:   AsPtr[Hours](150),
+				TaskDayTypes:     DayTypeMap{"vacation": VACATION_DAY},
+			}
+			global := &Common{weeklyHours: 3625}
+
+			records, err := HandleClockifyDetailedExportFile(config, strings.NewReader(clockifyExport(',', tt.rows)))
+			if err != nil {
+				t.Fatalf("unexpected import error: %v", err)
+			}
+			days, err := AggregateDays(config, records)
+			if err != nil {
+				t.Fatalf("unexpected aggregate error: %v", err)
+			}
+			ApplyDayTypes(config, days)
+
+			weeks := AggregateWeeks(config, days)
+			if len(*weeks) != tt.weeks {
+				t.Fatalf("got %v weeks, want %v", len(*weeks), tt.weeks)
+			}
+
+			var sb strings.Builder
+			if err := ExportCustomFormat(weeks, &sb); err != nil {
+				t.Fatalf("unexpected export error: %v", err)
+			}
+
+			entries, err := HandleCustomFile(bufio.NewScanner(strings.NewReader(sb.String())))
+			if err != nil {
+				t.Fatalf("unexpected parse error: %v", err)
+			}
+			if len(*entries) != tt.weeks {
+				t.Fatalf("parsed %v weeks, want %v:\n%s", len(*entries), tt.weeks, sb.String())
+			}
+
+			result := CheckCustomEntries(config, global, entries)
+			for _, row := range result.rows {
+				for _, msg := range row.errors {
+					t.Errorf("%s", *msg)
+				}
+			}
+			if last := (*weeks)[len(*weeks)-1]; result.balance != last.balance {
+				t.Errorf("got balance %v, want %v", result.balance, last.balance)
+			}
+		})
+	}
+}
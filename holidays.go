@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Plain decimal hours value on a holiday file line, e.g. 4 or 3,5
+var HOLIDAY_HOURS_REGEX = regexp.MustCompile(`^[0-9]+([,.][0-9]+)?$`)
+
+func NewHolidayCalendar(rules HolidayRuleFunc) *HolidayCalendar {
+	return &HolidayCalendar{
+		rules:   rules,
+		years:   map[int]bool{},
+		builtin: HolidayMap{},
+		custom:  HolidayMap{},
+	}
+}
+
+// Find holiday for the given date, nil if regular day
+// User defined holidays take precedence over built-in ones
+func (cal *HolidayCalendar) Lookup(date time.Time) *Holiday {
+	key := DateKey(date)
+	if h, ok := cal.custom[key]; ok {
+		return h
+	}
+	if cal.rules == nil {
+		return nil
+	}
+	// Built-in holidays generated lazily per year
+	if !cal.years[key.Year()] {
+		for _, h := range cal.rules(key.Year()) {
+			cal.builtin[DateKey(h.date)] = h
+		}
+		cal.years[key.Year()] = true
+	}
+	return cal.builtin[key]
+}
+
+// Read user defined holidays from file
+// Line format: <date> [required hours] [name]
+// Date in configured date layout, hours default to zero
+func (cal *HolidayCalendar) ReadFile(path *string, layout *string) (err error) {
+	f, err := os.Open(*path)
+
+	if err != nil {
+		fmt.Printf("ERROR: Failed to open holiday file (path: %s) Err: %s\n", *path, err.Error())
+		return err
+	}
+	defer f.Close()
+
+	// Date layout may consist of multiple words
+	dateFields := len(strings.Fields(*layout))
+
+	scanner := bufio.NewScanner(f)
+
+	var line Line = 0
+	for scanner.Scan() {
+		line++
+
+		rawstr := strings.TrimSpace(scanner.Text())
+
+		// Skip empty, skip commented out
+		if rawstr == "" || rawstr[0] == '#' {
+			continue
+		}
+
+		fields := strings.Fields(rawstr)
+		if len(fields) < dateFields {
+			fmt.Printf("ERROR: Holiday file: %s Line: %v: Could not parse date. Value: '%s'\n", *path, line, rawstr)
+			return errors.New("invalid line in holiday file")
+		}
+
+		date, err := time.Parse(*layout, strings.Join(fields[:dateFields], " "))
+		if err != nil {
+			fmt.Printf("ERROR: Holiday file: %s Line: %v: Could not parse date. Value: '%s' Err: %s\n", *path, line, rawstr, err.Error())
+			return err
+		}
+
+		h := &Holiday{date: DateKey(date)}
+		fields = fields[dateFields:]
+
+		// Optional reduced required hours, e.g. shortened eves
+		if len(fields) > 0 && HOLIDAY_HOURS_REGEX.MatchString(fields[0]) {
+			hours, err := ParseHours(&fields[0])
+			if err != nil {
+				fmt.Printf("ERROR: Holiday file: %s Line: %v: Could not parse hours. Value: '%s' Err: %s\n", *path, line, rawstr, err.Error())
+				return err
+			}
+			h.hours = &hours
+			fields = fields[1:]
+		}
+
+		if len(fields) > 0 {
+			h.name = AsPtr(strings.Join(fields, " "))
+		} else {
+			h.name = AsPtr("Holiday")
+		}
+
+		if _, ok := cal.custom[h.date]; ok {
+			fmt.Printf("WARNING: Holiday file: %s Line: %v: Date already defined, overriding. Value: '%s'\n", *path, line, rawstr)
+		}
+		cal.custom[h.date] = h
+	}
+
+	if err = scanner.Err(); err != nil {
+		fmt.Println("ERROR: Failed to read line from holiday file. Err:", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Easter Sunday of the given year (Gregorian, anonymous algorithm)
+func EasterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := ((h + l - 7*m + 114) % 31) + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// First given weekday on or after the date
+func NextWeekday(date time.Time, wd time.Weekday) time.Time {
+	return date.AddDate(0, 0, (int(wd)-int(date.Weekday())+7)%7)
+}
+
+// Finnish public holidays and de facto days off (eves) for the year
+func FinnishHolidays(year int) []*Holiday {
+	fixed := func(month time.Month, day int, name string) *Holiday {
+		return &Holiday{date: time.Date(year, month, day, 0, 0, 0, 0, time.UTC), name: AsPtr(name)}
+	}
+	easter := EasterSunday(year)
+	fromEaster := func(days int, name string) *Holiday {
+		return &Holiday{date: easter.AddDate(0, 0, days), name: AsPtr(name)}
+	}
+	midsummerEve := NextWeekday(time.Date(year, time.June, 19, 0, 0, 0, 0, time.UTC), time.Friday)
+	allSaints := NextWeekday(time.Date(year, time.October, 31, 0, 0, 0, 0, time.UTC), time.Saturday)
+
+	return []*Holiday{
+		fixed(time.January, 1, "Uudenvuodenpäivä"),
+		fixed(time.January, 6, "Loppiainen"),
+		fromEaster(-2, "Pitkäperjantai"),
+		fromEaster(0, "Pääsiäispäivä"),
+		fromEaster(1, "2. pääsiäispäivä"),
+		fixed(time.May, 1, "Vappu"),
+		fromEaster(39, "Helatorstai"),
+		fromEaster(49, "Helluntaipäivä"),
+		{date: midsummerEve, name: AsPtr("Juhannusaatto")},
+		{date: midsummerEve.AddDate(0, 0, 1), name: AsPtr("Juhannuspäivä")},
+		{date: allSaints, name: AsPtr("Pyhäinpäivä")},
+		fixed(time.December, 6, "Itsenäisyyspäivä"),
+		fixed(time.December, 24, "Jouluaatto"),
+		fixed(time.December, 25, "Joulupäivä"),
+		fixed(time.December, 26, "Tapaninpäivä"),
+	}
+}
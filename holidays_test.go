@@ -0,0 +1,144 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEasterSunday(t *testing.T) {
+	tests := []struct {
+		year int
+		want string
+	}{
+		{2000, "2000-04-23"},
+		{2019, "2019-04-21"},
+		{2023, "2023-04-09"},
+		{2024, "2024-03-31"},
+		{2025, "2025-04-20"},
+		{2038, "2038-04-25"},
+	}
+
+	for _, tt := range tests {
+		if got := EasterSunday(tt.year).Format(time.DateOnly); got != tt.want {
+			t.Errorf("EasterSunday(%v) = %s, want %s", tt.year, got, tt.want)
+		}
+	}
+}
+
+func TestFinnishHolidays(t *testing.T) {
+	tests := []struct {
+		date string
+		name string
+	}{
+		{"2024-01-01", "Uudenvuodenpäivä"},
+		{"2024-03-29", "Pitkäperjantai"},
+		{"2024-04-01", "2. pääsiäispäivä"},
+		{"2024-05-09", "Helatorstai"},
+		{"2024-06-21", "Juhannusaatto"},
+		{"2024-06-22", "Juhannuspäivä"},
+		{"2024-11-02", "Pyhäinpäivä"},
+		{"2023-06-23", "Juhannusaatto"},
+		{"2023-11-04", "Pyhäinpäivä"},
+		{"2024-12-24", "Jouluaatto"},
+		{"2024-04-30", ""},
+		{"2024-06-20", ""},
+	}
+
+	cal := NewHolidayCalendar(FinnishHolidays)
+	for _, tt := range tests {
+		date, _ := time.Parse(time.DateOnly, tt.date)
+		h := cal.Lookup(date)
+		if tt.name == "" {
+			if h != nil {
+				t.Errorf("%s: got holiday %s, want none", tt.date, *h.name)
+			}
+			continue
+		}
+		if h == nil || *h.name != tt.name {
+			t.Errorf("%s: got %v, want %s", tt.date, h, tt.name)
+		}
+	}
+}
+
+func TestHolidayCalendarReadFile(t *testing.T) {
+	type lookup struct {
+		date  string
+		name  string
+		hours *Hours
+	}
+
+	tests := []struct {
+		name    string
+		layout  string
+		content string
+		lookups []lookup
+		wantErr bool
+	}{
+		{
+			name:    "hours and names",
+			layout:  "02.01.2006",
+			content: "# comment\n30.04.2024 4,0 Vappuaatto\n27.12.2024 Company day off\n28.12.2024\n",
+			lookups: []lookup{
+				{"2024-04-30", "Vappuaatto", AsPtr[Hours](400)},
+				{"2024-12-27", "Company day off", nil},
+				{"2024-12-28", "Holiday", nil},
+			},
+		},
+		{
+			name:    "overrides built-in holiday",
+			layout:  "02.01.2006",
+			content: "24.12.2024 3,5 Short eve\n",
+			lookups: []lookup{
+				{"2024-12-24", "Short eve", AsPtr[Hours](350)},
+				{"2024-12-25", "Joulupäivä", nil},
+			},
+		},
+		{
+			name:    "layout of multiple words",
+			layout:  "Jan 2 2006",
+			content: "Apr 30 2024 4 Vappuaatto\n",
+			lookups: []lookup{
+				{"2024-04-30", "Vappuaatto", AsPtr[Hours](400)},
+			},
+		},
+		{
+			name:    "invalid date",
+			layout:  "02.01.2006",
+			content: "31.02.2024 Holiday\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "holidays.txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			cal := NewHolidayCalendar(FinnishHolidays)
+			err := cal.ReadFile(&path, &tt.layout)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, l := range tt.lookups {
+				date, _ := time.Parse(time.DateOnly, l.date)
+				h := cal.Lookup(date)
+				if h == nil || *h.name != l.name {
+					t.Errorf("%s: got %v, want %s", l.date, h, l.name)
+					continue
+				}
+				if (h.hours == nil) != (l.hours == nil) || (h.hours != nil && *h.hours != *l.hours) {
+					t.Errorf("%s: got hours %v, want %v", l.date, h.hours, l.hours)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Plain signed decimal value, comma or dot as decimal separator
+var HOURS_REGEX = regexp.MustCompile(`^([+\-]?)([0-9]+)(?:[,.]([0-9]+))?$`)
+
+// Parse decimal hours string (e.g. 7,25 or -12.00) into exact fixed-point value
+// Decimals beyond hundredths are rounded half away from zero
+func ParseHours(str *string) (Hours, error) {
+	if str == nil {
+		return 0, errors.New("ERROR: input ptr was null")
+	}
+	m := HOURS_REGEX.FindStringSubmatch(strings.TrimSpace(*str))
+	if m == nil {
+		return 0, fmt.Errorf("invalid hours value: '%s'", *str)
+	}
+
+	whole, err := strconv.ParseInt(m[2], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	// Pad or cut decimals to hundredths, remember the first cut digit for rounding
+	frac := m[3] + "00"
+	hundredths, _ := strconv.ParseInt(frac[:2], 10, 64)
+	val := Hours(whole)*HOURS_SCALE + Hours(hundredths)
+	if len(m[3]) > 2 && m[3][2] >= '5' {
+		val++
+	}
+
+	if m[1] == "-" {
+		val = -val
+	}
+	return val, nil
+}
+
+// Clock style duration, e.g. 7:30 or 07:30:15, hours may exceed 24
+var CLOCK_DURATION_REGEX = regexp.MustCompile(`^([0-9]+):([0-5][0-9])(?::([0-5][0-9]))?$`)
+
+// Parse clock style duration (H:MM or H:MM:SS) exactly
+func ParseClockDuration(str *string) (time.Duration, error) {
+	if str == nil {
+		return 0, errors.New("ERROR: input ptr was null")
+	}
+	m := CLOCK_DURATION_REGEX.FindStringSubmatch(strings.TrimSpace(*str))
+	if m == nil {
+		return 0, fmt.Errorf("invalid duration value: '%s'", *str)
+	}
+	h, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	min, _ := strconv.ParseInt(m[2], 10, 64)
+	sec, _ := strconv.ParseInt("0"+m[3], 10, 64)
+	return time.Duration(h)*time.Hour + time.Duration(min)*time.Minute + time.Duration(sec)*time.Second, nil
+}
+
+// Parse either clock style duration (7:30) or decimal hours (7,5 or 7.5)
+func ParseDuration(str *string) (Hours, error) {
+	if str != nil && strings.Contains(*str, ":") {
+		d, err := ParseClockDuration(str)
+		if err != nil {
+			return 0, err
+		}
+		return HoursFromDuration(d), nil
+	}
+	return ParseHours(str)
+}
+
+// Convert duration into hours, rounded to nearest hundredth
+func HoursFromDuration(d time.Duration) Hours {
+	unit := time.Hour / time.Duration(HOURS_SCALE)
+	if d < 0 {
+		return -Hours((-d + unit/2) / unit)
+	}
+	return Hours((d + unit/2) / unit)
+}
+
+// Decimal representation with two decimals, e.g. 7.25 or -12.00
+func (h Hours) String() string {
+	sign := ""
+	v := h
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/HOURS_SCALE, v%HOURS_SCALE)
+}
+
+// Approximate value for presentation purposes only, never for calculations
+func (h Hours) Float() float64 {
+	return float64(h) / float64(HOURS_SCALE)
+}
+
+// Decimal representation with comma separator as in custom files, e.g. 41,25
+// Signed adds plus sign for zero and positive values
+func (h Hours) StringFi(signed bool) string {
+	str := strings.Replace(h.String(), ".", ",", 1)
+	if signed && h >= 0 {
+		str = "+" + str
+	}
+	return str
+}
+
+// Plain JSON number, e.g. 7.25
+func (h Hours) MarshalJSON() ([]byte, error) {
+	return []byte(h.String()), nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseHours(t *testing.T) {
+	tests := []struct {
+		str     string
+		want    Hours
+		wantErr bool
+	}{
+		{str: "7,25", want: 725},
+		{str: "7.25", want: 725},
+		{str: "+5,0", want: 500},
+		{str: "-12.00", want: -1200},
+		{str: " 41,5 ", want: 4150},
+		{str: "8", want: 800},
+		{str: "0,005", want: 1},
+		{str: "0,004", want: 0},
+		{str: "-1,235", want: -124},
+		{str: "1,2", want: 120},
+		{str: "", wantErr: true},
+		{str: "7,", wantErr: true},
+		{str: "1.000,5", wantErr: true},
+		{str: "abc", wantErr: true},
+		{str: "(+0,75)", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseHours(&tt.str)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("'%s': expected error, got %v", tt.str, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("'%s': unexpected error: %v", tt.str, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("'%s': got %v, want %v", tt.str, int64(got), int64(tt.want))
+		}
+	}
+}
+
+func TestHoursFromDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want Hours
+	}{
+		{d: 8 * time.Hour, want: 800},
+		{d: 7*time.Hour + 15*time.Minute, want: 725},
+		{d: 20 * time.Minute, want: 33},
+		{d: 40 * time.Minute, want: 67},
+		{d: 18 * time.Second, want: 1},
+		{d: 17 * time.Second, want: 0},
+		{d: -40 * time.Minute, want: -67},
+	}
+
+	for _, tt := range tests {
+		if got := HoursFromDuration(tt.d); got != tt.want {
+			t.Errorf("%v: got %v, want %v", tt.d, int64(got), int64(tt.want))
+		}
+	}
+}
+
+func TestParseClockDuration(t *testing.T) {
+	tests := []struct {
+		str     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{str: "7:30", want: 7*time.Hour + 30*time.Minute},
+		{str: "07:30:15", want: 7*time.Hour + 30*time.Minute + 15*time.Second},
+		{str: " 26:00:00 ", want: 26 * time.Hour},
+		{str: "7:60", wantErr: true},
+		{str: "7.5", wantErr: true},
+		{str: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseClockDuration(&tt.str)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("'%s': expected error", tt.str)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("'%s': unexpected error: %v", tt.str, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("'%s': got %v, want %v", tt.str, got, tt.want)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		str  string
+		want Hours
+	}{
+		{str: "7:15", want: 725},
+		{str: "7,25", want: 725},
+		{str: "7.5", want: 750},
+		{str: "0:20", want: 33},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDuration(&tt.str)
+		if err != nil {
+			t.Errorf("'%s': unexpected error: %v", tt.str, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("'%s': got %v, want %v", tt.str, int64(got), int64(tt.want))
+		}
+	}
+}
+
+func TestHoursString(t *testing.T) {
+	tests := []struct {
+		h    Hours
+		want string
+		sign string
+	}{
+		{h: 725, want: "7.25", sign: "+7.25"},
+		{h: 5, want: "0.05", sign: "+0.05"},
+		{h: 0, want: "0.00", sign: "+0.00"},
+		{h: -1200, want: "-12.00", sign: "-12.00"},
+		{h: -5, want: "-0.05", sign: "-0.05"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.h.String(); got != tt.want {
+			t.Errorf("%v: got %s, want %s", int64(tt.h), got, tt.want)
+		}
+		if got := *PlusSignIfNecessary(tt.h); got != tt.sign {
+			t.Errorf("%v: got signed %s, want %s", int64(tt.h), got, tt.sign)
+		}
+	}
+}
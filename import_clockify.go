@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Clockify detailed report csv export
+type ClockifyImporter struct{}
+
+func init() {
+	RegisterImporter("clockify_export", &ClockifyImporter{})
+}
+
+func (imp *ClockifyImporter) Kind() ImportKind {
+	return DAY_IMPORT
+}
+
+func (imp *ClockifyImporter) Import(config *Config, reader io.Reader) (*ImportData, error) {
+	entries, err := HandleClockifyDetailedExportFile(config, reader)
+	if err != nil {
+		return nil, err
+	}
+	return &ImportData{entries: entries}, nil
+}
+
+func HandleClockifyDetailedExportFile(config *Config, reader io.Reader) (arr *ListTimeEntry, err error) {
+	rows, rowLines, colIndexes, err := ReadCsvRows(config, reader, config.ClockifyColumns, ClockifyColumnNameMapping, NewClockifyOptionalColumns())
+	if err != nil {
+		return nil, err
+	}
+
+	// Define parsed column indexes
+	columns := NewClockifyExportColumns()
+
+	entries := make(ListTimeEntry, 0, len(rows))
+
+	for i, cols := range rows {
+		line := rowLines[i]
+
+		entry := &TimeEntry{line: line}
+
+		// Handle only required columns for the row
+		for _, idx := range *columns {
+			// Skip optional columns not in file
+			colIdx, found := colIndexes[*idx]
+			if !found {
+				continue
+			}
+			// Position of the column in the row
+			pos := AsPtr((Column)(colIdx))
+			// Trim whitespace around column
+			// TODO ensure value is case insensitive in all cases!
+			colRaw := cols[*pos]
+			col := AsPtr(strings.ToLower(strings.TrimSpace(colRaw)))
+			switch *idx {
+			case COL_CLOCKIFY_TASK:
+				entry.tasks = append(entry.tasks, col)
+				entry.labels = append(entry.labels, col)
+			case COL_CLOCKIFY_PROJECT:
+				entry.labels = append(entry.labels, col)
+			case COL_CLOCKIFY_DATE:
+				entry.date, err = time.Parse(*config.DateParseLayout, *col)
+			case COL_CLOCKIFY_DURATION:
+				match := DECIMAL_REGEX.MatchString(*col)
+				if !match {
+					return nil, ErrorParse(line, pos, &colRaw, errors.New("could not parse column from row"))
+				}
+				entry.duration, err = ParseHours(col)
+			default:
+				fmt.Printf("ERROR: Row: %v Column: %v: Value: '%s': Tried to parse column for which parsing is undefined.\n", line, *pos, colRaw)
+				return nil, errors.New("behaviour not defined for column")
+			}
+			if err != nil {
+				return nil, ErrorParse(line, pos, &colRaw, err)
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return &entries, nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Clockify detailed export with the given rows of task, description, date and duration
+func clockifyExport(delim rune, rows [][4]string) string {
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	w.Comma = delim
+	_ = w.Write([]string{"Project", "Client", "Description", "Task", "User", "Group", "Email", "Tags", "Billable", "Start Date", "Start Time", "End Date", "End Time", "Duration (h)", "Duration (decimal)", "Billable Rate (EUR)", "Billable Amount (EUR)"})
+	for _, r := range rows {
+		_ = w.Write([]string{"Project X", "ACME", r[1], r[0], "Tester", "", "tester@example.com", "", "Yes", r[2], "08:00", r[2], "16:00", "08:00:00", r[3], "0.00", "0.00"})
+	}
+	w.Flush()
+	return sb.String()
+}
+
+func TestHandleClockifyDetailedExportFile(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse("02.01.2006", s)
+		return d
+	}
+
+	tests := []struct {
+		name    string
+		delim   rune
+		input   string
+		days    []SingleEntry
+		wantErr bool
+	}{
+		{
+			name:  "latest first, same day summed, missing weekday added",
+			delim: ',',
+			input: clockifyExport(',', [][4]string{
+				{"Development", "Review", "27.10.2023", "8.00"},
+				{"Development", "Planning, \"quoted\"\nsecond line", "25.10.2023", "4.50"},
+				{"Development", "Bug hunting", "25.10.2023", "3.25"},
+			}),
+			days: []SingleEntry{
+				{date: date("25.10.2023"), duration: 775},
+				{date: date("26.10.2023"), duration: 0},
+				{date: date("27.10.2023"), duration: 800},
+			},
+		},
+		{
+			name:  "semicolon delimiter and decimal comma",
+			delim: ';',
+			input: clockifyExport(';', [][4]string{
+				{"Development", "Review; part 2", "27.10.2023", "7,25"},
+			}),
+			days: []SingleEntry{
+				{date: date("27.10.2023"), duration: 725},
+			},
+		},
+		{
+			name:  "excluded task counted as zero",
+			delim: ',',
+			input: clockifyExport(',', [][4]string{
+				{"Lunch", "", "27.10.2023", "0.50"},
+				{"Development", "", "27.10.2023", "7.25"},
+			}),
+			days: []SingleEntry{
+				{date: date("27.10.2023"), duration: 725},
+			},
+		},
+		{
+			name:  "absence task marks day type",
+			delim: ',',
+			input: clockifyExport(',', [][4]string{
+				{"Vacation", "", "27.10.2023", "7.25"},
+			}),
+			days: []SingleEntry{
+				{date: date("27.10.2023"), duration: 0, dtype: VACATION_DAY},
+			},
+		},
+		{
+			name:    "too few columns",
+			delim:   ',',
+			input:   "Project,Client,Description\nProject X,ACME,Planning\n",
+			wantErr: true,
+		},
+		{
+			name:  "invalid duration",
+			delim: ',',
+			input: clockifyExport(',', [][4]string{
+				{"Development", "", "27.10.2023", "abc"},
+			}),
+			wantErr: true,
+		},
+		{
+			name:    "header only",
+			delim:   ',',
+			input:   clockifyExport(',', nil),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				ClockifyColumns:  NewClockifyColumnAliases(),
+				CsvDelimiter:     tt.delim,
+				DateParseLayout:  AsPtr("02.01.2006"),
+				ExcludedWeekdays: &ListWeekday{AsPtr(time.Saturday), AsPtr(time.Sunday)},
+				ExcludedTasks:    &ListString{AsPtr("lunch")},
+				TaskDayTypes:     DayTypeMap{"vacation": VACATION_DAY},
+			}
+			entries, err := HandleClockifyDetailedExportFile(config, strings.NewReader(tt.input))
+			// Days are combined after importing, as with all import files
+			var arr *ListSingleEntry
+			if err == nil {
+				arr, err = AggregateDays(config, entries)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(*arr) != len(tt.days) {
+				t.Fatalf("got %v days, want %v", len(*arr), len(tt.days))
+			}
+			for i, want := range tt.days {
+				got := (*arr)[i]
+				if !got.date.Equal(want.date) || got.duration != want.duration || got.dtype != want.dtype {
+					t.Errorf("day %v: got %s %v (%v), want %s %v (%v)", i, got.date.Format("02.01.2006"), got.duration, got.dtype, want.date.Format("02.01.2006"), want.duration, want.dtype)
+				}
+			}
+		})
+	}
+}
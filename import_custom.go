@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Handmade multi-line weekly format, see samples/manual-report-2023.txt
+type CustomImporter struct{}
+
+func init() {
+	RegisterImporter("custom", &CustomImporter{})
+}
+
+func (imp *CustomImporter) Kind() ImportKind {
+	return WEEK_IMPORT
+}
+
+func (imp *CustomImporter) Import(config *Config, reader io.Reader) (*ImportData, error) {
+	weeks, err := HandleCustomFile(bufio.NewScanner(reader))
+	if err != nil {
+		return nil, err
+	}
+	return &ImportData{weeks: weeks}, nil
+}
+
+func HandleCustomFile(scanner *bufio.Scanner) (arr *ListWeekEntry, err error) {
+	var line Line = 0
+
+	arr = AsPtr(make(ListWeekEntry, 0, 1024))
+
+	fieldMapping := *NewCustomFileMapping()
+
+	// Assume new entry at start
+	entry := &WeekEntry{}
+
+	var year Year
+	var month Month
+
+	processLastEntry := func() {
+		// If any of fields not set, consider last row failed
+		if !fieldMapping.FieldsOk() {
+			fmt.Printf("NOTE: Failed to parse previous entry: %+v\n", entry)
+			// DONT EXIT => opportunistic, ignore nonrelevant rows and move forward
+		} else {
+			// If all flags ok, we can add the entry
+			*arr = append(*arr, entry)
+		}
+	}
+
+	// List all conditions to skip the current line parsing
+	checkIfSkipLine := func(str *string) (match bool) {
+		if strings.HasPrefix(*str, "---") {
+			return true
+		}
+		return ValidateMonthYearRow(str)
+	}
+ToNextRow:
+	for scanner.Scan() {
+		line++
+		if err = scanner.Err(); err != nil {
+			fmt.Println("ERROR: Failed to read line from input file. Err:", err.Error())
+			return
+		}
+
+		// Retrieve line
+		rawLine := scanner.Text()
+
+		// Trim line (remove whitespace from edges)
+		rawstr := strings.TrimSpace(rawLine)
+
+		// Skip "" "\n" ...
+		// Counts as new entry starts
+		if rawstr == "" {
+			// Process the last entry
+			processLastEntry()
+			// On newline/whitespace only row, reset prev entry after processing in any case
+			entry = &WeekEntry{}
+			fieldMapping.ResetFields()
+			// And skip the empty line
+			continue ToNextRow
+		}
+
+		// Keep track of the current year-month
+		yr, mth, err := ParseMonthYearRow(&rawstr)
+		// If month, year parsed ok
+		if err == nil {
+			// Update stored values
+			year = yr
+			month = mth
+		}
+
+		// Determine if line is to be skipped
+		if checkIfSkipLine(&rawstr) {
+			continue ToNextRow
+		}
+
+		// Every entry, set the current
+		entry.year = year
+		entry.month = month
+
+		// Loop all fields IN ASCENDING ORDER
+		var field int
+		for field = 0; field < len(fieldMapping); field++ {
+			// if current field not filled yet
+			if fieldMapping[field] {
+				continue
+			}
+			start := strings.Index(rawLine, rawstr)
+			ok := ParseCustomField(entry, field, &rawstr, &FieldPos{line: line, start: start, end: start + len(rawstr)})
+			// Comment is optional field - always set true after iteration
+			if field == 1 {
+				fieldMapping[field] = true
+			}
+			if ok {
+				// Required fields - only set to true if parsed ok
+				fieldMapping[field] = true
+				// move to next row if parsed ok
+				continue ToNextRow
+			}
+		} // for each field
+		// After passing all values for entry, go next
+	} // for each file line
+	// After last line, process the last entry after EOF
+	processLastEntry()
+	return arr, nil
+}
+
+// Parse a single field of a custom file entry from raw value
+// Shared by both custom file formats, see NewCustomFileMapping for fields
+// Returns true if the value was parsed and stored into the entry
+// Value position is recorded for diff and balance fields
+func ParseCustomField(entry *WeekEntry, field int, rawstr *string, pos *FieldPos) (ok bool) {
+	line := pos.line
+	switch field {
+	case 0:
+		// Date Range: X.Y.-A.B. or X.-A.B.
+		match := DATERANGE_REGEX.MatchString(*rawstr)
+		if !match {
+			fmt.Printf("ERROR: Line: %v: Could not parse row field: %v. Value: %s\n", line, field, *rawstr)
+			return false
+		}
+		entry.trange = AsPtr(*rawstr)
+		// Without month header the dates are unknown, plain weekly hours are required
+		if entry.year == 0 {
+			break
+		}
+		start, end, err := ParseWeekRange(rawstr, entry.year, entry.month)
+		if err != nil {
+			fmt.Printf("ERROR: Line: %v: Could not parse row field: %v date range from: %s, Err: %s\n", line, field, *rawstr, err.Error())
+			return false
+		}
+		entry.start = start
+		entry.end = end
+	case 1:
+		// Comment AABBCC
+		match := COMMENT_REGEX.MatchString(*rawstr)
+		if !match {
+			fmt.Printf("WARNING: Line: %v: Could not parse row field: %v. Value: %s\n", line, field, *rawstr)
+			return false
+		}
+		entry.comment = AsPtr(*rawstr)
+		entry.dayTypes = ParseCommentDayTypes(entry, rawstr)
+	case 2:
+		// Worked XX,YY
+		match := DECIMAL_REGEX.MatchString(*rawstr)
+		if !match {
+			fmt.Printf("ERROR: Line: %v: Could not parse row field: %v. Value: %s\n", line, field, *rawstr)
+			return false
+		}
+		worked, err := ParseHours(rawstr)
+		if err != nil {
+			fmt.Printf("ERROR: Line: %v: Could not parse row field: %v worked value from: %s as hours, Err: %s\n", line, field, *rawstr, err.Error())
+			return false
+		}
+		entry.worked = worked
+	case 3:
+		// Diff to weekly limit +X,YY
+		match := SIGNED_DECIMAL_REGEX.MatchString(*rawstr)
+		if !match {
+			fmt.Printf("ERROR: Line: %v: Could not parse row field: %v. Value: %s\n", line, field, *rawstr)
+			return false
+		}
+		diff, err := ParseHours(rawstr)
+		if err != nil {
+			fmt.Printf("ERROR: Line: %v: Could not parse field: %v diff value from: %s as hours, Err: %s\n", line, field, *rawstr, err.Error())
+			return false
+		}
+		entry.diff = diff
+		entry.diffPos = pos
+	case 4:
+		// Balance (+X,YY)
+		match := PAREN_SIGNED_DECIMAL_REGEX.MatchString(*rawstr)
+		if !match {
+			fmt.Printf("ERROR: Line: %v: Could not parse row field: %v. Value: %s\n", line, field, *rawstr)
+			return false
+		}
+		balance, err := ParseHours(AsPtr(StrRemoveParentheses(rawstr)))
+		if err != nil {
+			fmt.Printf("ERROR: Line: %v: Could not parse field: %v balance value from: %s as hours, Err: %s\n", line, field, *rawstr, err.Error())
+			return false
+		}
+		entry.balance = balance
+		entry.balancePos = pos
+	default:
+		fmt.Printf("ERROR: Line: %v: Tried to parse field: %v for which parsing is undefined.\n", line, field)
+		return false
+	}
+	return true
+}
+
+// Collect day types annotated in a comment, e.g. VACATION 3.10. 4.10. SICK 6.10.
+// Annotated days have to be within the date range of the entry
+func ParseCommentDayTypes(entry *WeekEntry, comment *string) (dayTypes DayTypeDateMap) {
+	if entry.start.IsZero() {
+		if DAYTYPE_COMMENT_REGEX.MatchString(*comment) {
+			fmt.Printf("WARNING: Entry (%s): Dates unknown without month header, annotated days ignored.\n", *entry.trange)
+		}
+		return nil
+	}
+	for _, m := range DAYTYPE_COMMENT_REGEX.FindAllStringSubmatch(*comment, -1) {
+		dtype := DayTypeMapping[strings.ToLower(m[1])]
+		for _, dm := range DAYMONTH_REGEX.FindAllStringSubmatch(m[2], -1) {
+			day, _ := strconv.Atoi(dm[1])
+			month, _ := strconv.Atoi(dm[2])
+			found := false
+			// Range may cross the year
+			for _, year := range []int{entry.start.Year(), entry.end.Year()} {
+				date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+				if !date.Before(entry.start) && !date.After(entry.end) {
+					if dayTypes == nil {
+						dayTypes = DayTypeDateMap{}
+					}
+					dayTypes[date] = dtype
+					found = true
+					break
+				}
+			}
+			if !found {
+				fmt.Printf("WARNING: Entry (%s): Annotated day %s not within the date range, ignored.\n", *entry.trange, dm[0])
+			}
+		}
+	}
+	return dayTypes
+}
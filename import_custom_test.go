@@ -0,0 +1,70 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCommentDayTypes(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse("02.01.2006", s)
+		return d
+	}
+
+	tests := []struct {
+		name     string
+		start    string
+		end      string
+		comment  string
+		dayTypes DayTypeDateMap
+	}{
+		{
+			name:     "types and dates",
+			start:    "02.10.2023",
+			end:      "08.10.2023",
+			comment:  "VACATION 3.10. 4.10. SICK 6.10.",
+			dayTypes: DayTypeDateMap{date("03.10.2023"): VACATION_DAY, date("04.10.2023"): VACATION_DAY, date("06.10.2023"): SICK_DAY},
+		},
+		{
+			name:     "range crosses year",
+			start:    "30.12.2024",
+			end:      "05.01.2025",
+			comment:  "HOLIDAY 1.1. FLEX 31.12.",
+			dayTypes: DayTypeDateMap{date("01.01.2025"): HOLIDAY_DAY, date("31.12.2024"): FLEX_OFF_DAY},
+		},
+		{
+			name:    "day outside range ignored",
+			start:   "02.10.2023",
+			end:     "08.10.2023",
+			comment: "SICK 10.10.",
+		},
+		{
+			name:    "plain comment",
+			start:   "02.10.2023",
+			end:     "08.10.2023",
+			comment: "Release week",
+		},
+		{
+			name:    "dates unknown",
+			comment: "VACATION 3.10.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := &WeekEntry{trange: AsPtr(tt.name)}
+			if tt.start != "" {
+				entry.start, entry.end = date(tt.start), date(tt.end)
+			}
+			got := ParseCommentDayTypes(entry, &tt.comment)
+			if len(got) != len(tt.dayTypes) {
+				t.Fatalf("got %v, want %v", got, tt.dayTypes)
+			}
+			for d, want := range tt.dayTypes {
+				if got[d] != want {
+					t.Errorf("%s: got %v, want %v", d.Format("02.01.2006"), got[d], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Compact weekly format, see HandleSimpleCustomFile
+type CustomShortImporter struct{}
+
+func init() {
+	RegisterImporter("customshort", &CustomShortImporter{})
+}
+
+func (imp *CustomShortImporter) Kind() ImportKind {
+	return WEEK_IMPORT
+}
+
+func (imp *CustomShortImporter) Import(config *Config, reader io.Reader) (*ImportData, error) {
+	weeks, err := HandleSimpleCustomFile(bufio.NewScanner(reader))
+	if err != nil {
+		return nil, err
+	}
+	return &ImportData{weeks: weeks}, nil
+}
+
+// Compact custom file format, one line per week:
+// 2.10.-8.10. 41,25 +5,0 (+0,75) OPTIONAL COMMENT
+// Month headers (YYYY-MM) and separator lines are handled as in custom file
+func HandleSimpleCustomFile(scanner *bufio.Scanner) (arr *ListWeekEntry, err error) {
+	var line Line = 0
+
+	arr = AsPtr(make(ListWeekEntry, 0, 1024))
+
+	var year Year
+	var month Month
+
+	// Field order on the line, comment is the optional remainder
+	lineFields := []int{0, 2, 3, 4}
+
+ToNextRow:
+	for scanner.Scan() {
+		line++
+		if err = scanner.Err(); err != nil {
+			fmt.Println("ERROR: Failed to read line from input file. Err:", err.Error())
+			return
+		}
+
+		rawLine := scanner.Text()
+		rawstr := strings.TrimSpace(rawLine)
+
+		// Skip empty and separator lines
+		if rawstr == "" || strings.HasPrefix(rawstr, "---") {
+			continue ToNextRow
+		}
+
+		// Keep track of the current year-month
+		yr, mth, err := ParseMonthYearRow(&rawstr)
+		if err == nil {
+			year = yr
+			month = mth
+			continue ToNextRow
+		}
+
+		// Whitespace separated columns with their positions on the line
+		colIdx := WORD_REGEX.FindAllStringIndex(rawLine, -1)
+		cols := make([]string, 0, len(colIdx))
+		for _, idx := range colIdx {
+			cols = append(cols, rawLine[idx[0]:idx[1]])
+		}
+		if len(cols) < len(lineFields) {
+			fmt.Printf("ERROR: Line: %v: Expected at least %v fields, found %v. Value: %s\n", line, len(lineFields), len(cols), rawstr)
+			continue ToNextRow
+		}
+
+		entry := &WeekEntry{year: year, month: month}
+
+		for i, field := range lineFields {
+			if !ParseCustomField(entry, field, &cols[i], &FieldPos{line: line, start: colIdx[i][0], end: colIdx[i][1]}) {
+				// Opportunistic, ignore failed row and move forward
+				fmt.Printf("NOTE: Failed to parse entry on line: %v\n", line)
+				continue ToNextRow
+			}
+		}
+
+		// Everything after the balance is considered comment
+		if len(cols) > len(lineFields) {
+			comment := strings.Join(cols[len(lineFields):], " ")
+			_ = ParseCustomField(entry, 1, &comment, &FieldPos{line: line, start: colIdx[len(lineFields)][0], end: len(rawLine)})
+		}
+
+		*arr = append(*arr, entry)
+	}
+
+	return arr, nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func TestHandleSimpleCustomFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		entries []WeekEntry
+	}{
+		{
+			name:  "weeks under month headers",
+			input: "--------------------\n2023-10\n--------------------\n2.10.-8.10. 41,25 +5,0 (+0,75)\n16.-22.10. 38,75 +2,5 (+6,5)\n",
+			entries: []WeekEntry{
+				{trange: AsPtr("2.10.-8.10."), year: 2023, month: 10, worked: 4125, diff: 500, balance: 75},
+				{trange: AsPtr("16.-22.10."), year: 2023, month: 10, worked: 3875, diff: 250, balance: 650},
+			},
+		},
+		{
+			name:  "comment after balance",
+			input: "2023-12\n25.-31.12. 21,75 -14,5 (-2,0) HOLIDAYS 25.12. 26.12.\n",
+			entries: []WeekEntry{
+				{trange: AsPtr("25.-31.12."), comment: AsPtr("HOLIDAYS 25.12. 26.12."), year: 2023, month: 12, worked: 2175, diff: -1450, balance: -200},
+			},
+		},
+		{
+			name:  "no month header",
+			input: "2.10.-8.10. 41,25 +5,0 (+0,75)\n",
+			entries: []WeekEntry{
+				{trange: AsPtr("2.10.-8.10."), worked: 4125, diff: 500, balance: 75},
+			},
+		},
+		{
+			name:    "too few fields skipped",
+			input:   "2023-10\n2.10.-8.10. 41,25 +5,0\n",
+			entries: []WeekEntry{},
+		},
+		{
+			name:  "invalid value skipped",
+			input: "2023-10\n2.10.-8.10. abc +5,0 (+0,75)\n9.10.-15.10. 39,5 +3,25 (+4,0)\n",
+			entries: []WeekEntry{
+				{trange: AsPtr("9.10.-15.10."), year: 2023, month: 10, worked: 3950, diff: 325, balance: 400},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arr, err := HandleSimpleCustomFile(bufio.NewScanner(strings.NewReader(tt.input)))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(*arr) != len(tt.entries) {
+				t.Fatalf("got %v entries, want %v", len(*arr), len(tt.entries))
+			}
+			for i, want := range tt.entries {
+				got := (*arr)[i]
+				if *got.trange != *want.trange || got.year != want.year || got.month != want.month {
+					t.Errorf("entry %v: got %s %v-%v, want %s %v-%v", i, *got.trange, got.year, got.month, *want.trange, want.year, want.month)
+				}
+				if got.worked != want.worked || got.diff != want.diff || got.balance != want.balance {
+					t.Errorf("entry %v: got %v %v (%v), want %v %v (%v)", i, got.worked, got.diff, got.balance, want.worked, want.diff, want.balance)
+				}
+				if (got.comment == nil) != (want.comment == nil) || (got.comment != nil && *got.comment != *want.comment) {
+					t.Errorf("entry %v: got comment %v, want %v", i, got.comment, want.comment)
+				}
+			}
+		})
+	}
+}
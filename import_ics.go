@@ -0,0 +1,508 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// iCalendar file with work logged as events
+type IcsImporter struct{}
+
+func init() {
+	RegisterImporter("ics", &IcsImporter{})
+}
+
+func (imp *IcsImporter) Kind() ImportKind {
+	return DAY_IMPORT
+}
+
+func (imp *IcsImporter) Import(config *Config, reader io.Reader) (*ImportData, error) {
+	entries, err := HandleIcsFile(config, reader, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &ImportData{entries: entries}, nil
+}
+
+// Read content lines, continuation lines (starting with space or tab) are joined
+func readIcsProperties(reader io.Reader) (props []*IcsProperty, err error) {
+	scanner := bufio.NewScanner(reader)
+	// Folded lines are short, but unfolded values may be long
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var line Line = 0
+	var cur *IcsProperty
+	var raw strings.Builder
+
+	flush := func() error {
+		if cur == nil {
+			return nil
+		}
+		err := parseIcsProperty(raw.String(), cur)
+		if err != nil {
+			fmt.Printf("ERROR: Line: %v: Could not parse content line: '%s'. Double check input file.\n", cur.line, raw.String())
+			return err
+		}
+		props = append(props, cur)
+		cur = nil
+		raw.Reset()
+		return nil
+	}
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t") {
+			if cur != nil {
+				raw.WriteString(text[1:])
+			}
+			continue
+		}
+		if err = flush(); err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		cur = &IcsProperty{line: line}
+		raw.WriteString(text)
+	}
+	if err = scanner.Err(); err != nil {
+		fmt.Println("ERROR: Failed to read line from input file. Err:", err.Error())
+		return nil, err
+	}
+	if err = flush(); err != nil {
+		return nil, err
+	}
+
+	return props, nil
+}
+
+// NAME;PARAM=VALUE;PARAM="QUOTED:VALUE":VALUE
+func parseIcsProperty(raw string, prop *IcsProperty) error {
+	quoted := false
+	sep := -1
+	for i, r := range raw {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			sep = i
+			break
+		}
+	}
+	if sep < 0 {
+		return errors.New("missing value separator")
+	}
+
+	parts := strings.Split(raw[:sep], ";")
+	prop.name = strings.ToUpper(strings.TrimSpace(parts[0]))
+	prop.params = map[string]string{}
+	for _, p := range parts[1:] {
+		k, v, found := strings.Cut(p, "=")
+		if !found {
+			return errors.New("invalid parameter")
+		}
+		prop.params[strings.ToUpper(k)] = strings.Trim(v, "\"")
+	}
+	prop.value = raw[sep+1:]
+
+	if prop.name == "" {
+		return errors.New("missing property name")
+	}
+	return nil
+}
+
+// Split list value on unescaped commas and unescape the text
+func splitIcsText(value string) (list []string) {
+	var cur strings.Builder
+	escaped := false
+	for _, r := range value {
+		switch {
+		case escaped:
+			if r == 'n' || r == 'N' {
+				r = '\n'
+			}
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ',':
+			list = append(list, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	return append(list, cur.String())
+}
+
+// Parse DATE or DATE-TIME value
+// UTC (Z suffix), TZID parameter or floating local time
+func parseIcsTime(prop *IcsProperty) (t time.Time, allDay bool, err error) {
+	value := strings.TrimSpace(prop.value)
+
+	loc := time.Local
+	if tzid, ok := prop.params["TZID"]; ok {
+		loc, err = time.LoadLocation(tzid)
+		if err != nil {
+			fmt.Printf("WARNING: Line: %v: Unknown time zone: '%s', using local time.\n", prop.line, tzid)
+			loc = time.Local
+		}
+	}
+
+	if prop.params["VALUE"] == "DATE" || len(value) == len(ICS_DATE_LAYOUT) {
+		t, err = time.ParseInLocation(ICS_DATE_LAYOUT, value, time.Local)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err = time.Parse(ICS_DATETIME_LAYOUT, strings.TrimSuffix(value, "Z"))
+		return t, false, err
+	}
+	t, err = time.ParseInLocation(ICS_DATETIME_LAYOUT, value, loc)
+	return t, false, err
+}
+
+// ISO 8601 duration, e.g. PT1H30M or P1D
+func parseIcsDuration(value string) (time.Duration, error) {
+	m := ICS_DURATION_REGEX.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil {
+		return 0, fmt.Errorf("invalid duration value: '%s'", value)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+2])
+		if err != nil {
+			return 0, err
+		}
+		d += time.Duration(n) * unit
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+// FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;UNTIL=20231231T235959Z
+// Unsupported rule parts are ignored with a warning
+func parseIcsRule(prop *IcsProperty) (rule *IcsRule, err error) {
+	rule = &IcsRule{interval: 1}
+	for _, part := range strings.Split(prop.value, ";") {
+		k, v, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			rule.freq = strings.ToUpper(v)
+			if !slices.Contains([]string{"DAILY", "WEEKLY", "MONTHLY", "YEARLY"}, rule.freq) {
+				return nil, fmt.Errorf("unsupported frequency: '%s'", v)
+			}
+		case "INTERVAL":
+			rule.interval, err = strconv.Atoi(v)
+			if err != nil || rule.interval <= 0 {
+				return nil, fmt.Errorf("invalid interval: '%s'", v)
+			}
+		case "COUNT":
+			rule.count, err = strconv.Atoi(v)
+			if err != nil || rule.count <= 0 {
+				return nil, fmt.Errorf("invalid count: '%s'", v)
+			}
+		case "UNTIL":
+			until, _, err := parseIcsTime(&IcsProperty{value: v, params: map[string]string{}, line: prop.line})
+			if err != nil {
+				return nil, fmt.Errorf("invalid until: '%s'", v)
+			}
+			// Date only until includes the whole day
+			if len(v) == len(ICS_DATE_LAYOUT) {
+				until = until.AddDate(0, 0, 1).Add(-time.Second)
+			}
+			rule.until = &until
+		case "BYDAY":
+			for _, d := range strings.Split(v, ",") {
+				wd, ok := IcsWeekdayMapping[strings.ToLower(d)]
+				if !ok {
+					return nil, fmt.Errorf("unsupported weekday: '%s'", d)
+				}
+				rule.byDay = append(rule.byDay, *wd)
+			}
+		case "WKST":
+			// Weeks always start on monday
+		default:
+			fmt.Printf("WARNING: Line: %v: Unsupported recurrence rule part: '%s' ignored.\n", prop.line, part)
+		}
+	}
+	if rule.freq == "" {
+		return nil, errors.New("missing frequency")
+	}
+	return rule, nil
+}
+
+// Start times of the occurrences from start until limit
+// Wall clock time is kept in the time zone of start
+func (rule *IcsRule) Expand(start time.Time, limit time.Time) (occurrences []time.Time) {
+	count := 0
+	// Beyond the end of the rule or the limit
+	past := func(t time.Time) bool {
+		return (rule.until != nil && t.After(*rule.until)) || t.After(limit)
+	}
+	// Returns false when no more occurrences
+	add := func(t time.Time) bool {
+		if past(t) {
+			return false
+		}
+		if t.Before(start) {
+			return true
+		}
+		occurrences = append(occurrences, t)
+		count++
+		return rule.count == 0 || count < rule.count
+	}
+
+	// Weekdays in order from monday
+	byDay := slices.Clone(rule.byDay)
+	slices.SortFunc(byDay, func(a, b time.Weekday) int { return (int(a)+6)%7 - (int(b)+6)%7 })
+
+	for period := 0; period < ICS_MAX_PERIODS; period++ {
+		step := period * rule.interval
+		switch rule.freq {
+		case "DAILY":
+			t := start.AddDate(0, 0, step)
+			if len(byDay) > 0 && !slices.Contains(byDay, t.Weekday()) {
+				if past(t) {
+					return
+				}
+				continue
+			}
+			if !add(t) {
+				return
+			}
+		case "WEEKLY":
+			monday := start.AddDate(0, 0, -((int(start.Weekday())+6)%7)+7*step)
+			days := byDay
+			if len(days) <= 0 {
+				days = []time.Weekday{start.Weekday()}
+			}
+			for _, wd := range days {
+				if !add(monday.AddDate(0, 0, (int(wd)+6)%7)) {
+					return
+				}
+			}
+		case "MONTHLY", "YEARLY":
+			var t time.Time
+			if rule.freq == "MONTHLY" {
+				t = start.AddDate(0, step, 0)
+			} else {
+				t = start.AddDate(step, 0, 0)
+			}
+			// Skip months without the day, e.g. 31st
+			if t.Day() != start.Day() {
+				if past(t) {
+					return
+				}
+				continue
+			}
+			if !add(t) {
+				return
+			}
+		}
+	}
+	return
+}
+
+// Parse events from the content lines
+// Nested components (e.g. alarms) and other components are skipped
+func parseIcsEvents(props []*IcsProperty) (events []*IcsEvent, err error) {
+	var event *IcsEvent
+	// Components inside event
+	depth := 0
+
+	for _, prop := range props {
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT") && event == nil:
+			event = &IcsEvent{line: prop.line}
+			continue
+		case prop.name == "BEGIN" && event != nil:
+			depth++
+			continue
+		case prop.name == "END" && event != nil && depth > 0:
+			depth--
+			continue
+		case prop.name == "END" && strings.EqualFold(prop.value, "VEVENT") && event != nil:
+			if event.start == nil {
+				fmt.Printf("ERROR: Line: %v: Event without DTSTART. Double check input file.\n", event.line)
+				return nil, errors.New("event without start")
+			}
+			events = append(events, event)
+			event = nil
+			continue
+		}
+		if event == nil || depth > 0 {
+			continue
+		}
+
+		switch prop.name {
+		case "UID":
+			event.uid = AsPtr(prop.value)
+		case "DTSTART":
+			t, allDay, err := parseIcsTime(prop)
+			if err != nil {
+				return nil, icsErrorParse(prop, err)
+			}
+			event.start = &t
+			event.allDay = allDay
+		case "DTEND":
+			t, _, err := parseIcsTime(prop)
+			if err != nil {
+				return nil, icsErrorParse(prop, err)
+			}
+			event.end = &t
+		case "DURATION":
+			d, err := parseIcsDuration(prop.value)
+			if err != nil {
+				return nil, icsErrorParse(prop, err)
+			}
+			event.duration = &d
+		case "RRULE":
+			event.rule, err = parseIcsRule(prop)
+			if err != nil {
+				return nil, icsErrorParse(prop, err)
+			}
+		case "EXDATE":
+			for _, v := range strings.Split(prop.value, ",") {
+				t, _, err := parseIcsTime(&IcsProperty{value: v, params: prop.params, line: prop.line})
+				if err != nil {
+					return nil, icsErrorParse(prop, err)
+				}
+				event.exdates = append(event.exdates, t)
+			}
+		case "RECURRENCE-ID":
+			t, _, err := parseIcsTime(prop)
+			if err != nil {
+				return nil, icsErrorParse(prop, err)
+			}
+			event.recurrenceId = &t
+		case "SUMMARY":
+			name := AsPtr(strings.ToLower(strings.TrimSpace(strings.Join(splitIcsText(prop.value), ","))))
+			event.tasks = append(event.tasks, name)
+			event.labels = append(event.labels, name)
+		case "CATEGORIES":
+			for _, c := range splitIcsText(prop.value) {
+				if c = strings.ToLower(strings.TrimSpace(c)); c != "" {
+					event.tasks = append(event.tasks, &c)
+					event.labels = append(event.labels, &c)
+				}
+			}
+		case "STATUS":
+			event.cancelled = strings.EqualFold(prop.value, "CANCELLED")
+		}
+	}
+
+	if event != nil {
+		fmt.Printf("ERROR: Line: %v: Event not ended before end of file. Double check input file.\n", event.line)
+		return nil, errors.New("event not ended")
+	}
+
+	return events, nil
+}
+
+func icsErrorParse(prop *IcsProperty, err error) error {
+	fmt.Printf("ERROR: Line: %v: Could not parse property: %s Value: '%s'. Double check input file.\n", prop.line, prop.name, prop.value)
+	return err
+}
+
+// Events are split into days, timed events at midnight of local time
+// All-day events mark the day type only, e.g. vacation, their hours are not work
+// Recurring events are expanded until now, summary and categories are matched against excluded tasks
+func HandleIcsFile(config *Config, reader io.Reader, now time.Time) (arr *ListTimeEntry, err error) {
+	props, err := readIcsProperties(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := parseIcsEvents(props)
+	if err != nil {
+		return nil, err
+	}
+
+	// Modified or cancelled occurrences replace the occurrence of the recurring event
+	overrides := map[string][]time.Time{}
+	for _, e := range events {
+		if e.recurrenceId != nil && e.uid != nil {
+			overrides[*e.uid] = append(overrides[*e.uid], *e.recurrenceId)
+		}
+	}
+
+	entries := make(ListTimeEntry, 0, len(events))
+
+	for _, e := range events {
+		if e.cancelled {
+			continue
+		}
+
+		// Length of the event, all-day events last whole days
+		var duration time.Duration
+		days := 1
+		switch {
+		case e.allDay && e.end != nil:
+			days = int(math.Round(e.end.Sub(*e.start).Hours() / 24))
+		case e.allDay && e.duration != nil:
+			days = int(math.Round(e.duration.Hours() / 24))
+		case e.end != nil:
+			duration = e.end.Sub(*e.start)
+		case e.duration != nil:
+			duration = *e.duration
+		}
+		if duration < 0 || days < 0 {
+			fmt.Printf("ERROR: Line: %v: Event ends before it starts. Double check input file.\n", e.line)
+			return nil, errors.New("event ends before start")
+		}
+
+		starts := []time.Time{*e.start}
+		if e.rule != nil && e.recurrenceId == nil {
+			starts = e.rule.Expand(*e.start, now)
+		}
+
+		excluded := e.exdates
+		if e.uid != nil && e.recurrenceId == nil {
+			excluded = append(slices.Clone(excluded), overrides[*e.uid]...)
+		}
+
+	ToNextOccurrence:
+		for _, start := range starts {
+			for _, ex := range excluded {
+				if ex.Equal(start) || (e.allDay && ex.Year() == start.Year() && ex.YearDay() == start.YearDay()) {
+					continue ToNextOccurrence
+				}
+			}
+			// Planned future events are not yet worked
+			if start.After(now) {
+				continue
+			}
+
+			if e.allDay {
+				for d := 0; d == 0 || d < days; d++ {
+					date := start.AddDate(0, 0, d)
+					entries = append(entries, &TimeEntry{date: time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC), line: e.line, tasks: e.tasks, labels: e.labels})
+				}
+				continue
+			}
+
+			dates, durations := SplitAtMidnight(start.In(time.Local), duration)
+			for i := range dates {
+				entries = append(entries, &TimeEntry{date: dates[i], duration: HoursFromDuration(durations[i]), line: e.line, tasks: e.tasks, labels: e.labels})
+			}
+		}
+	}
+
+	fmt.Printf("Processed %v events from input file.\n", len(events))
+
+	return &entries, nil
+}
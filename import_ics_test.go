@@ -0,0 +1,261 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseIcsRule(t *testing.T) {
+	tests := []struct {
+		value    string
+		freq     string
+		interval int
+		count    int
+		until    string
+		byDay    []time.Weekday
+		wantErr  bool
+	}{
+		{value: "FREQ=DAILY;COUNT=5", freq: "DAILY", interval: 1, count: 5},
+		{value: "freq=weekly;interval=2;byday=MO,WE", freq: "WEEKLY", interval: 2, byDay: []time.Weekday{time.Monday, time.Wednesday}},
+		{value: "FREQ=MONTHLY;UNTIL=20231231", freq: "MONTHLY", interval: 1, until: "2023-12-31T23:59:59Z"},
+		{value: "FREQ=YEARLY;UNTIL=20231231T120000Z;WKST=MO", freq: "YEARLY", interval: 1, until: "2023-12-31T12:00:00Z"},
+		{value: "FREQ=HOURLY", wantErr: true},
+		{value: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{value: "FREQ=DAILY;COUNT=x", wantErr: true},
+		{value: "FREQ=WEEKLY;BYDAY=1MO", wantErr: true},
+		{value: "COUNT=3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			rule, err := parseIcsRule(&IcsProperty{name: "RRULE", params: map[string]string{}, value: tt.value})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rule.freq != tt.freq || rule.interval != tt.interval || rule.count != tt.count {
+				t.Errorf("got %s/%v/%v, want %s/%v/%v", rule.freq, rule.interval, rule.count, tt.freq, tt.interval, tt.count)
+			}
+			if (rule.until == nil) != (tt.until == "") || (rule.until != nil && rule.until.UTC().Format(time.RFC3339) != tt.until) {
+				t.Errorf("got until %v, want %s", rule.until, tt.until)
+			}
+			if len(rule.byDay) != len(tt.byDay) {
+				t.Errorf("got weekdays %v, want %v", rule.byDay, tt.byDay)
+			}
+		})
+	}
+}
+
+func TestIcsRuleExpand(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse("02.01.2006", s)
+		return d
+	}
+
+	tests := []struct {
+		name  string
+		rule  string
+		start string
+		limit string
+		want  []string
+	}{
+		{
+			name:  "daily count",
+			rule:  "FREQ=DAILY;COUNT=3",
+			start: "09.10.2023",
+			limit: "31.12.2023",
+			want:  []string{"09.10.2023", "10.10.2023", "11.10.2023"},
+		},
+		{
+			name:  "daily weekdays only",
+			rule:  "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR;COUNT=3",
+			start: "06.10.2023",
+			limit: "31.12.2023",
+			want:  []string{"06.10.2023", "09.10.2023", "10.10.2023"},
+		},
+		{
+			name:  "weekly by day until",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=WE,MO;UNTIL=20231020",
+			start: "02.10.2023",
+			limit: "31.12.2023",
+			want:  []string{"02.10.2023", "04.10.2023", "16.10.2023", "18.10.2023"},
+		},
+		{
+			name:  "limit before end of rule",
+			rule:  "FREQ=DAILY",
+			start: "09.10.2023",
+			limit: "10.10.2023",
+			want:  []string{"09.10.2023", "10.10.2023"},
+		},
+		{
+			name:  "monthly on day 31 with count",
+			rule:  "FREQ=MONTHLY;COUNT=3",
+			start: "31.01.2023",
+			limit: "31.12.2099",
+			want:  []string{"31.01.2023", "31.03.2023", "31.05.2023"},
+		},
+		{
+			name:  "monthly on day 31 until",
+			rule:  "FREQ=MONTHLY;UNTIL=20230430",
+			start: "31.01.2023",
+			limit: "31.12.2099",
+			want:  []string{"31.01.2023", "31.03.2023"},
+		},
+		{
+			name:  "yearly on leap day",
+			rule:  "FREQ=YEARLY",
+			start: "29.02.2020",
+			limit: "01.03.2028",
+			want:  []string{"29.02.2020", "29.02.2024", "29.02.2028"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := parseIcsRule(&IcsProperty{name: "RRULE", params: map[string]string{}, value: tt.rule})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := rule.Expand(date(tt.start), date(tt.limit))
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i, want := range tt.want {
+				if !got[i].Equal(date(want)) {
+					t.Errorf("occurrence %v: got %s, want %s", i, got[i].Format("02.01.2006"), want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseIcsDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "PT1H30M", want: 90 * time.Minute},
+		{value: "P1D", want: 24 * time.Hour},
+		{value: "P1W", want: 7 * 24 * time.Hour},
+		{value: "-PT15M", want: -15 * time.Minute},
+		{value: "1H", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseIcsDuration(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("'%s': expected error", tt.value)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("'%s': got %v (%v), want %v", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestHandleIcsFile(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse("02.01.2006", s)
+		return d
+	}
+	calendar := func(events ...string) string {
+		return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + strings.Join(events, "") + "END:VCALENDAR\r\n"
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		days    []SingleEntry
+		wantErr bool
+	}{
+		{
+			name: "recurring with exdate and override",
+			input: calendar(
+				"BEGIN:VEVENT\r\nUID:work\r\nSUMMARY:Development\r\nDTSTART:20231002T080000Z\r\nDTEND:20231002T120000Z\r\n"+
+					"RRULE:FREQ=DAILY;COUNT=4\r\nEXDATE:20231003T080000Z\r\nEND:VEVENT\r\n",
+				"BEGIN:VEVENT\r\nUID:work\r\nRECURRENCE-ID:20231004T080000Z\r\nSUMMARY:Development\r\n"+
+					"DTSTART:20231004T080000Z\r\nDURATION:PT2H\r\nEND:VEVENT\r\n",
+			),
+			days: []SingleEntry{
+				{date: date("02.10.2023"), duration: 400},
+				{date: date("03.10.2023")},
+				{date: date("04.10.2023"), duration: 200},
+				{date: date("05.10.2023"), duration: 400},
+				{date: date("06.10.2023")},
+			},
+		},
+		{
+			name: "all-day absence and folded line",
+			input: calendar(
+				"BEGIN:VEVENT\r\nUID:vacation\r\nSUMMARY:Vaca\r\n tion\r\nDTSTART;VALUE=DATE:20231005\r\nDTEND;VALUE=DATE:20231007\r\nEND:VEVENT\r\n",
+			),
+			days: []SingleEntry{
+				{date: date("05.10.2023"), dtype: VACATION_DAY},
+				{date: date("06.10.2023"), dtype: VACATION_DAY},
+			},
+		},
+		{
+			name: "future and cancelled events skipped",
+			input: calendar(
+				"BEGIN:VEVENT\r\nUID:a\r\nSUMMARY:Development\r\nDTSTART:20231006T080000Z\r\nDTEND:20231006T100000Z\r\nEND:VEVENT\r\n",
+				"BEGIN:VEVENT\r\nUID:b\r\nSUMMARY:Development\r\nSTATUS:CANCELLED\r\nDTSTART:20231006T100000Z\r\nDTEND:20231006T120000Z\r\nEND:VEVENT\r\n",
+				"BEGIN:VEVENT\r\nUID:c\r\nSUMMARY:Development\r\nDTSTART:20241006T080000Z\r\nDTEND:20241006T100000Z\r\nEND:VEVENT\r\n",
+			),
+			days: []SingleEntry{
+				{date: date("06.10.2023"), duration: 200},
+			},
+		},
+		{
+			name:    "event ends before start",
+			input:   calendar("BEGIN:VEVENT\r\nUID:a\r\nDTSTART:20231006T080000Z\r\nDTEND:20231006T070000Z\r\nEND:VEVENT\r\n"),
+			wantErr: true,
+		},
+		{
+			name:    "event not ended",
+			input:   calendar("BEGIN:VEVENT\r\nUID:a\r\nDTSTART:20231006T080000Z\r\n"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				ExcludedWeekdays: &ListWeekday{AsPtr(time.Saturday), AsPtr(time.Sunday)},
+				TaskDayTypes:     DayTypeMap{"vacation": VACATION_DAY},
+			}
+			entries, err := HandleIcsFile(config, strings.NewReader(tt.input), date("01.01.2024"))
+			// Days are combined after importing, as with all import files
+			var arr *ListSingleEntry
+			if err == nil {
+				arr, err = AggregateDays(config, entries)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(*arr) != len(tt.days) {
+				t.Fatalf("got %v days, want %v", len(*arr), len(tt.days))
+			}
+			for i, want := range tt.days {
+				got := (*arr)[i]
+				if !got.date.Equal(want.date) || got.duration != want.duration || got.dtype != want.dtype {
+					t.Errorf("day %v: got %s %v (%v), want %s %v (%v)", i, got.date.Format("02.01.2006"), got.duration, got.dtype, want.date.Format("02.01.2006"), want.duration, want.dtype)
+				}
+			}
+		})
+	}
+}
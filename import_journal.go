@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Journal written by the punch clock commands
+type JournalImporter struct{}
+
+func init() {
+	RegisterImporter("journal", &JournalImporter{})
+}
+
+func (imp *JournalImporter) Kind() ImportKind {
+	return DAY_IMPORT
+}
+
+func (imp *JournalImporter) Import(config *Config, reader io.Reader) (*ImportData, error) {
+	entries, err := HandleJournalFile(config, reader, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &ImportData{entries: entries}, nil
+}
+
+// Sessions into time records, running session is counted until now
+func HandleJournalFile(config *Config, reader io.Reader, now time.Time) (arr *ListTimeEntry, err error) {
+	sessions, err := ReplayJournal(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(ListTimeEntry, 0, len(sessions))
+	for _, s := range sessions {
+		if s.end == nil {
+			fmt.Printf("NOTE: Session started at %s is still running. Counted until now.\n", s.start.Format(time.DateTime))
+		}
+		entries = append(entries, SessionEntries(s, now)...)
+	}
+
+	fmt.Printf("Processed %v sessions from journal file.\n", len(sessions))
+
+	return &entries, nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandleJournalFile(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse("02.01.2006", s)
+		return d
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		days    []SingleEntry
+		wantErr bool
+	}{
+		{
+			name:  "session past midnight",
+			input: "2023-10-05T20:00:00Z start\n2023-10-05T23:00:00Z break\n2023-10-05T23:30:00Z resume\n2023-10-06T02:30:00Z stop\n",
+			days: []SingleEntry{
+				{date: date("05.10.2023"), duration: 350},
+				{date: date("06.10.2023"), duration: 250},
+			},
+		},
+		{
+			name:  "running session counted until now",
+			input: "2023-10-06T08:00:00Z start\n",
+			days: []SingleEntry{
+				{date: date("06.10.2023"), duration: 400},
+			},
+		},
+		{
+			name:    "invalid journal",
+			input:   "2023-10-06T08:00:00Z stop\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{ExcludedWeekdays: &ListWeekday{AsPtr(time.Saturday), AsPtr(time.Sunday)}}
+			now, _ := time.Parse(time.RFC3339, "2023-10-06T12:00:00Z")
+			entries, err := HandleJournalFile(config, strings.NewReader(tt.input), now)
+			// Days are combined after importing, as with all import files
+			var arr *ListSingleEntry
+			if err == nil {
+				arr, err = AggregateDays(config, entries)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(*arr) != len(tt.days) {
+				t.Fatalf("got %v days, want %v", len(*arr), len(tt.days))
+			}
+			for i, want := range tt.days {
+				got := (*arr)[i]
+				if !got.date.Equal(want.date) || got.duration != want.duration {
+					t.Errorf("day %v: got %s %v, want %s %v", i, got.date.Format("02.01.2006"), got.duration, want.date.Format("02.01.2006"), want.duration)
+				}
+			}
+		})
+	}
+}
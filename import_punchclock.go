@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Plain clock-in/clock-out log, one work session per line:
+// 2023-10-02 08:12 16:45 lunch 0:30
+type PunchClockImporter struct{}
+
+func init() {
+	RegisterImporter("punchclock", &PunchClockImporter{})
+}
+
+func (imp *PunchClockImporter) Kind() ImportKind {
+	return DAY_IMPORT
+}
+
+func (imp *PunchClockImporter) Import(config *Config, reader io.Reader) (*ImportData, error) {
+	entries, err := HandlePunchClockFile(config, bufio.NewScanner(reader))
+	if err != nil {
+		return nil, err
+	}
+	return &ImportData{entries: entries}, nil
+}
+
+// Line: <date> <start> <end> [<break name> <break duration>]...
+// Session ending before its start continues past midnight
+// Empty lines and lines starting with # are skipped
+// Every invalid line is reported before failing
+func HandlePunchClockFile(config *Config, scanner *bufio.Scanner) (arr *ListTimeEntry, err error) {
+	var line Line = 0
+	failed := 0
+
+	entries := make(ListTimeEntry, 0, 1024)
+
+ToNextRow:
+	for scanner.Scan() {
+		line++
+
+		rawstr := strings.TrimSpace(scanner.Text())
+		if rawstr == "" || strings.HasPrefix(rawstr, "#") {
+			continue ToNextRow
+		}
+
+		cols := strings.Fields(rawstr)
+		fail := func(col int, err error) {
+			_ = ErrorParse(line, AsPtr((Column)(col)), &cols[col], err)
+			failed++
+		}
+
+		if len(cols) < 3 || len(cols)%2 == 0 {
+			fmt.Printf("ERROR: Row: %v: Expected date, start and end followed by break name and duration pairs. Value: '%s'. Double check input file.\n", line, rawstr)
+			failed++
+			continue ToNextRow
+		}
+
+		date, err := parseExportDate(config, &cols[0])
+		if err != nil {
+			fail(0, err)
+			continue ToNextRow
+		}
+
+		var times [2]time.Time
+		for i := range times {
+			t, err := time.Parse(SHORT_TIME_LAYOUT, cols[i+1])
+			if err != nil {
+				fail(i+1, err)
+				continue ToNextRow
+			}
+			times[i] = date.Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute)
+		}
+		start, end := times[0], times[1]
+		if end.Equal(start) {
+			fail(2, errors.New("session end equals start"))
+			continue ToNextRow
+		}
+		if end.Before(start) {
+			end = end.AddDate(0, 0, 1)
+		}
+
+		var breaks time.Duration
+		for i := 4; i < len(cols); i += 2 {
+			d, err := ParseClockDuration(&cols[i])
+			if err != nil {
+				fail(i, err)
+				continue ToNextRow
+			}
+			breaks += d
+		}
+		if breaks > end.Sub(start) {
+			fail(4, errors.New("breaks longer than the session"))
+			continue ToNextRow
+		}
+
+		entries = append(entries, SessionTimeEntries(start, end, breaks, line)...)
+	}
+	if err = scanner.Err(); err != nil {
+		fmt.Println("ERROR: Failed to read line from input file. Err:", err.Error())
+		return nil, err
+	}
+
+	if failed > 0 {
+		fmt.Printf("ERROR: %v invalid line(s) in input file.\n", failed)
+		return nil, errors.New("invalid lines in punch clock file")
+	}
+
+	return &entries, nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandlePunchClockFile(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse("02.01.2006", s)
+		return d
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		days    []SingleEntry
+		wantErr bool
+	}{
+		{
+			name:  "sessions, breaks and comments",
+			input: "# comment\n2023-10-02 08:00 16:00 lunch 0:30 coffee 0:15\n\n2023-10-03 08:00 12:00\n2023-10-03 12:30 15:45\n",
+			days: []SingleEntry{
+				{date: date("02.10.2023"), duration: 725},
+				{date: date("03.10.2023"), duration: 725},
+				{date: date("04.10.2023")},
+				{date: date("05.10.2023")},
+				{date: date("06.10.2023")},
+			},
+		},
+		{
+			name:  "past midnight, breaks from first day",
+			input: "05.10.2023 20:00 02:30 break 0:30\n",
+			days: []SingleEntry{
+				{date: date("05.10.2023"), duration: 350},
+				{date: date("06.10.2023"), duration: 250},
+			},
+		},
+		{
+			name:    "missing break duration",
+			input:   "2023-10-02 08:00 16:00 lunch\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid time",
+			input:   "2023-10-02 8am 16:00\n",
+			wantErr: true,
+		},
+		{
+			name:    "end equals start",
+			input:   "2023-10-02 08:00 08:00\n",
+			wantErr: true,
+		},
+		{
+			name:    "breaks longer than session",
+			input:   "2023-10-02 08:00 09:00 lunch 1:30\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				DateParseLayout:  AsPtr("02.01.2006"),
+				ExcludedWeekdays: &ListWeekday{AsPtr(time.Saturday), AsPtr(time.Sunday)},
+			}
+			entries, err := HandlePunchClockFile(config, bufio.NewScanner(strings.NewReader(tt.input)))
+			// Days are combined after importing, as with all import files
+			var arr *ListSingleEntry
+			if err == nil {
+				arr, err = AggregateDays(config, entries)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(*arr) != len(tt.days) {
+				t.Fatalf("got %v days, want %v", len(*arr), len(tt.days))
+			}
+			for i, want := range tt.days {
+				got := (*arr)[i]
+				if !got.date.Equal(want.date) || got.duration != want.duration {
+					t.Errorf("day %v: got %s %v, want %s %v", i, got.date.Format("02.01.2006"), got.duration, want.date.Format("02.01.2006"), want.duration)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestResolveColumns(t *testing.T) {
+	// Header with the name at position, other columns unnamed
+	wide := func(pos int, name string) []string {
+		header := make([]string, pos+1)
+		header[0], header[1], header[pos] = "task", "start date", name
+		return header
+	}
+
+	tests := []struct {
+		name    string
+		header  []string
+		indexes ColumnIndexMap
+		wantErr bool
+	}{
+		{
+			name:    "default names in any order",
+			header:  []string{"Project", "Duration (decimal)", "Task", "Start Date"},
+			indexes: ColumnIndexMap{COL_CLOCKIFY_PROJECT: 0, COL_CLOCKIFY_TASK: 2, COL_CLOCKIFY_DATE: 3, COL_CLOCKIFY_DURATION: 1},
+		},
+		{
+			name:    "byte order mark, case and spaces",
+			header:  []string{"\uFEFFTASK", " start date ", "Duration (Decimal)"},
+			indexes: ColumnIndexMap{COL_CLOCKIFY_TASK: 0, COL_CLOCKIFY_DATE: 1, COL_CLOCKIFY_DURATION: 2},
+		},
+		{
+			name:    "alias from config",
+			header:  []string{"Tehtävä", "Start Date", "Duration (decimal)"},
+			indexes: ColumnIndexMap{COL_CLOCKIFY_TASK: 0, COL_CLOCKIFY_DATE: 1, COL_CLOCKIFY_DURATION: 2},
+		},
+		{
+			name:    "first matching column used",
+			header:  []string{"Task", "Task", "Start Date", "Duration (decimal)"},
+			indexes: ColumnIndexMap{COL_CLOCKIFY_TASK: 0, COL_CLOCKIFY_DATE: 2, COL_CLOCKIFY_DURATION: 3},
+		},
+		{
+			name:    "missing required column",
+			header:  []string{"Task", "Start Date"},
+			wantErr: true,
+		},
+		{
+			name:    "last supported position",
+			header:  wide(COL_MAX_POSITION, "duration (decimal)"),
+			indexes: ColumnIndexMap{COL_CLOCKIFY_TASK: 0, COL_CLOCKIFY_DATE: 1, COL_CLOCKIFY_DURATION: COL_MAX_POSITION},
+		},
+		{
+			name:    "position out of range",
+			header:  wide(COL_MAX_POSITION+1, "duration (decimal)"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aliases := NewClockifyColumnAliases()
+			*(*aliases)[COL_CLOCKIFY_TASK] = append(*(*aliases)[COL_CLOCKIFY_TASK], AsPtr("tehtävä"))
+
+			indexes, err := ResolveColumns(tt.header, aliases, ClockifyColumnNameMapping, NewClockifyOptionalColumns())
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(indexes) != len(tt.indexes) {
+				t.Fatalf("got %v columns, want %v", len(indexes), len(tt.indexes))
+			}
+			for col, want := range tt.indexes {
+				if got, ok := indexes[col]; !ok || got != want {
+					t.Errorf("column %v: got %v, want %v", col, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseImporter(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    ImportKind
+		wantErr bool
+	}{
+		{name: "custom", kind: WEEK_IMPORT},
+		{name: "customshort", kind: WEEK_IMPORT},
+		{name: "clockify_export", kind: DAY_IMPORT},
+		{name: "unknown", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			importer, err := ParseImporter(&tt.name)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if importer.Kind() != tt.kind {
+				t.Errorf("got kind %v, want %v", importer.Kind(), tt.kind)
+			}
+		})
+	}
+}
+
+func TestParseImportFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   [][][4]string
+		days    int
+		worked  Hours
+		wantErr bool
+	}{
+		{
+			name: "consecutive files merged",
+			files: [][][4]string{
+				{{"Development", "", "03.10.2023", "7.25"}, {"Development", "", "02.10.2023", "8.00"}},
+				{{"Development", "", "05.10.2023", "7.00"}, {"Development", "", "04.10.2023", "7.25"}},
+			},
+			days:   5,
+			worked: 2950,
+		},
+		{
+			name: "empty file skipped",
+			files: [][][4]string{
+				{{"Development", "", "02.10.2023", "8.00"}},
+				{},
+			},
+			days:   5,
+			worked: 800,
+		},
+		{
+			name: "overlapping dates",
+			files: [][][4]string{
+				{{"Development", "", "04.10.2023", "7.25"}, {"Development", "", "02.10.2023", "8.00"}},
+				{{"Development", "", "03.10.2023", "7.00"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			importer := ImporterRegistry["clockify_export"]
+			files := make(ListImportFile, 0, len(tt.files))
+			for i, rows := range tt.files {
+				name := filepath.Join(dir, string(rune('a'+i))+".csv")
+				if err := os.WriteFile(name, []byte(clockifyExport(',', rows)), 0644); err != nil {
+					t.Fatal(err)
+				}
+				files = append(files, &ImportFile{path: AsPtr(name), name: AsPtr(filepath.Base(name)), fileType: AsPtr("clockify_export"), importer: importer})
+			}
+			config := &Config{
+				ClockifyColumns:  NewClockifyColumnAliases(),
+				CsvDelimiter:     ',',
+				DateParseLayout:  AsPtr("02.01.2006"),
+				DailyHours:       725,
+				ExcludedWeekdays: &ListWeekday{AsPtr(time.Saturday), AsPtr(time.Sunday)},
+				ImportFiles:      &files,
+			}
+
+			data, err := ParseImportFile(config)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(*data.days) != tt.days {
+				t.Fatalf("got %v days, want %v", len(*data.days), tt.days)
+			}
+			var worked Hours
+			for _, d := range *data.days {
+				worked += d.duration
+			}
+			if worked != tt.worked {
+				t.Errorf("got worked %v, want %v", worked, tt.worked)
+			}
+		})
+	}
+}
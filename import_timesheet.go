@@ -0,0 +1,75 @@
+package main
+
+import (
+	"io"
+	"strings"
+)
+
+// Simple timesheet csv export with date, duration, project and task columns
+// Used for both Harvest time reports and Kimai exports
+type TimesheetImporter struct {
+	aliases func() *ColumnAliasMap
+	names   ColumnNameMap
+}
+
+func init() {
+	RegisterImporter("harvest_export", &TimesheetImporter{aliases: NewHarvestColumnAliases, names: HarvestColumnNameMapping})
+	RegisterImporter("kimai_export", &TimesheetImporter{aliases: NewKimaiColumnAliases, names: KimaiColumnNameMapping})
+}
+
+func (imp *TimesheetImporter) Kind() ImportKind {
+	return DAY_IMPORT
+}
+
+func (imp *TimesheetImporter) Import(config *Config, reader io.Reader) (*ImportData, error) {
+	entries, err := HandleTimesheetExportFile(config, reader, imp.aliases(), imp.names)
+	if err != nil {
+		return nil, err
+	}
+	return &ImportData{entries: entries}, nil
+}
+
+// Duration either decimal hours (7.5) or clock style (7:30)
+// Both project and task are matched against excluded tasks
+func HandleTimesheetExportFile(config *Config, reader io.Reader, aliases *ColumnAliasMap, names ColumnNameMap) (arr *ListTimeEntry, err error) {
+	rows, rowLines, colIndexes, err := ReadCsvRows(config, reader, aliases, names, NewSheetOptionalColumns())
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(ListTimeEntry, 0, len(rows))
+
+	for i, cols := range rows {
+		line := rowLines[i]
+		entry := &TimeEntry{line: line}
+
+		for _, col := range []Column{COL_SHEET_DATE, COL_SHEET_DURATION, COL_SHEET_PROJECT, COL_SHEET_TASK} {
+			// Skip optional columns not in file
+			idx, found := colIndexes[col]
+			if !found {
+				continue
+			}
+			pos := AsPtr((Column)(idx))
+			colRaw := cols[idx]
+			val := AsPtr(strings.ToLower(strings.TrimSpace(colRaw)))
+			switch col {
+			case COL_SHEET_DATE:
+				entry.date, err = parseExportDate(config, val)
+			case COL_SHEET_DURATION:
+				entry.duration, err = ParseDuration(val)
+			case COL_SHEET_PROJECT, COL_SHEET_TASK:
+				if *val != "" {
+					entry.tasks = append(entry.tasks, val)
+					entry.labels = append(entry.labels, val)
+				}
+			}
+			if err != nil {
+				return nil, ErrorParse(line, pos, &colRaw, err)
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return &entries, nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandleTimesheetExportFile(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse("02.01.2006", s)
+		return d
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		input   string
+		days    []SingleEntry
+		wantErr bool
+	}{
+		{
+			name: "harvest decimal hours and absence task",
+			file: "harvest_export",
+			input: "Date,Client,Project,Task,Notes,Hours\n" +
+				"2023-10-02,Acme,Website,Development,\"Frontend, forms\",4.5\n" +
+				"2023-10-02,Acme,Website,Development,Backend,2.75\n" +
+				"2023-10-04,Internal,Time Off,Vacation,,7.25\n",
+			days: []SingleEntry{
+				{date: date("02.10.2023"), duration: 725},
+				{date: date("03.10.2023")},
+				{date: date("04.10.2023"), dtype: VACATION_DAY},
+				{date: date("05.10.2023")},
+				{date: date("06.10.2023")},
+			},
+		},
+		{
+			name: "kimai clock durations and excluded activity",
+			file: "kimai_export",
+			input: "Date,From,To,Duration,Customer,Project,Activity,Description\n" +
+				"06.10.2023,08:00,12:00,4:00,Acme,Website,Development,\"Backend, API\"\n" +
+				"06.10.2023,12:00,12:30,0:30,Acme,Website,Lunch,\n" +
+				"06.10.2023,12:30,15:45,3:15,Acme,Website,Development,\n",
+			days: []SingleEntry{
+				{date: date("06.10.2023"), duration: 725},
+			},
+		},
+		{
+			name:    "harvest invalid hours",
+			file:    "harvest_export",
+			input:   "Date,Project,Task,Hours\n2023-10-02,Website,Development,many\n",
+			wantErr: true,
+		},
+		{
+			name:    "kimai missing duration column",
+			file:    "kimai_export",
+			input:   "Date,Project,Activity\n2023-10-02,Website,Development\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				CsvDelimiter:     ',',
+				DateParseLayout:  AsPtr("02.01.2006"),
+				ExcludedWeekdays: &ListWeekday{AsPtr(time.Saturday), AsPtr(time.Sunday)},
+				ExcludedTasks:    &ListString{AsPtr("lunch")},
+				TaskDayTypes:     DayTypeMap{"vacation": VACATION_DAY},
+			}
+			importer, err := ParseImporter(&tt.file)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			data, err := importer.Import(config, strings.NewReader(tt.input))
+			// Days are combined after importing, as with all import files
+			if err == nil {
+				data.days, err = AggregateDays(config, data.entries)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(*data.days) != len(tt.days) {
+				t.Fatalf("got %v days, want %v", len(*data.days), len(tt.days))
+			}
+			for i, want := range tt.days {
+				got := (*data.days)[i]
+				if !got.date.Equal(want.date) || got.duration != want.duration || got.dtype != want.dtype {
+					t.Errorf("day %v: got %s %v (%v), want %s %v (%v)", i, got.date.Format("02.01.2006"), got.duration, got.dtype, want.date.Format("02.01.2006"), want.duration, want.dtype)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Toggl Track detailed report csv export
+type TogglImporter struct{}
+
+func init() {
+	RegisterImporter("toggl_export", &TogglImporter{})
+}
+
+func (imp *TogglImporter) Kind() ImportKind {
+	return DAY_IMPORT
+}
+
+func (imp *TogglImporter) Import(config *Config, reader io.Reader) (*ImportData, error) {
+	entries, err := HandleTogglDetailedExportFile(config, reader)
+	if err != nil {
+		return nil, err
+	}
+	return &ImportData{entries: entries}, nil
+}
+
+// Parse date of the export, ISO format or the date layout of config
+func parseExportDate(config *Config, str *string) (time.Time, error) {
+	date, err := time.Parse(ISO_DATE_LAYOUT, *str)
+	if err != nil && config.DateParseLayout != nil {
+		date, err = time.Parse(*config.DateParseLayout, *str)
+	}
+	return date, err
+}
+
+// Rows have start date, start time and duration (HH:MM:SS)
+// Entries continuing past midnight are split into both days
+// Task and description are matched against excluded tasks
+func HandleTogglDetailedExportFile(config *Config, reader io.Reader) (arr *ListTimeEntry, err error) {
+	rows, rowLines, colIndexes, err := ReadCsvRows(config, reader, NewTogglColumnAliases(), TogglColumnNameMapping, NewTogglOptionalColumns())
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(ListTimeEntry, 0, len(rows))
+
+	for i, cols := range rows {
+		line := rowLines[i]
+
+		// Returns the trimmed value and its position, nil if column not in file
+		column := func(c Column) (*string, *Column) {
+			idx, found := colIndexes[c]
+			if !found {
+				return nil, nil
+			}
+			return AsPtr(strings.TrimSpace(cols[idx])), AsPtr((Column)(idx))
+		}
+
+		dateRaw, datePos := column(COL_TOGGL_DATE)
+		start, err := parseExportDate(config, dateRaw)
+		if err != nil {
+			return nil, ErrorParse(line, datePos, dateRaw, err)
+		}
+		if timeRaw, timePos := column(COL_TOGGL_TIME); timeRaw != nil && *timeRaw != "" {
+			t, err := time.Parse(ISO_TIME_LAYOUT, *timeRaw)
+			if err != nil {
+				t, err = time.Parse(SHORT_TIME_LAYOUT, *timeRaw)
+			}
+			if err != nil {
+				return nil, ErrorParse(line, timePos, timeRaw, err)
+			}
+			start = start.Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second)
+		}
+
+		durRaw, durPos := column(COL_TOGGL_DURATION)
+		duration, err := ParseClockDuration(durRaw)
+		if err != nil {
+			return nil, ErrorParse(line, durPos, durRaw, errors.New("could not parse column from row"))
+		}
+
+		var tasks, labels ListString
+		for _, c := range []Column{COL_TOGGL_TASK, COL_TOGGL_DESCRIPTION, COL_TOGGL_PROJECT} {
+			if v, _ := column(c); v != nil && *v != "" {
+				name := AsPtr(strings.ToLower(*v))
+				if c != COL_TOGGL_PROJECT {
+					tasks = append(tasks, name)
+				}
+				labels = append(labels, name)
+			}
+		}
+		// Multiple tags in one column, e.g. "vacation, billable"
+		if v, _ := column(COL_TOGGL_TAGS); v != nil {
+			for _, tag := range strings.Split(*v, ",") {
+				if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+					labels = append(labels, &tag)
+				}
+			}
+		}
+
+		dates, durations := SplitAtMidnight(start, duration)
+		if len(dates) > 1 {
+			fmt.Printf("NOTE: Row: %v: Entry continues past midnight, split into %v days.\n", line, len(dates))
+		}
+		for j := range dates {
+			entries = append(entries, &TimeEntry{date: dates[j], duration: HoursFromDuration(durations[j]), line: line, tasks: tasks, labels: labels})
+		}
+	}
+
+	return &entries, nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandleTogglDetailedExportFile(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse("02.01.2006", s)
+		return d
+	}
+	header := "User,Email,Client,Project,Task,Description,Billable,Start date,Start time,End date,End time,Duration,Tags\n"
+
+	tests := []struct {
+		name    string
+		input   string
+		days    []SingleEntry
+		wantErr bool
+	}{
+		{
+			name: "quoted description and day gap",
+			input: header +
+				"A,a@example.com,Acme,Website,,\"Backend work, API\",Yes,2023-10-02,08:00:00,2023-10-02,12:00:00,04:00:00,\n" +
+				"A,a@example.com,Acme,Website,,Frontend work,Yes,2023-10-02,12:30:00,2023-10-02,16:00:00,03:15:00,\n" +
+				"A,a@example.com,Acme,Website,,Frontend work,Yes,2023-10-04,08:00:00,2023-10-04,15:15:00,07:15:00,\n",
+			days: []SingleEntry{
+				{date: date("02.10.2023"), duration: 725},
+				{date: date("03.10.2023")},
+				{date: date("04.10.2023"), duration: 725},
+				{date: date("05.10.2023")},
+				{date: date("06.10.2023")},
+			},
+		},
+		{
+			name: "split past midnight and absence tag",
+			input: header +
+				"A,a@example.com,,Absences,,Day off,No,2023-10-04,08:00:00,2023-10-04,08:00:00,00:00:00,Vacation\n" +
+				"A,a@example.com,Acme,Operations,,Release,Yes,2023-10-05,20:00:00,2023-10-06,02:30:00,06:30:00,\"Overtime, Billable\"\n",
+			days: []SingleEntry{
+				{date: date("04.10.2023"), dtype: VACATION_DAY},
+				{date: date("05.10.2023"), duration: 400},
+				{date: date("06.10.2023"), duration: 250},
+			},
+		},
+		{
+			name:    "invalid duration",
+			input:   header + "A,a@example.com,Acme,Website,,Work,Yes,2023-10-02,08:00:00,2023-10-02,12:00:00,4h,\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid date",
+			input:   header + "A,a@example.com,Acme,Website,,Work,Yes,02/10/2023,08:00:00,2023-10-02,12:00:00,04:00:00,\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				CsvDelimiter:     ',',
+				DateParseLayout:  AsPtr("02.01.2006"),
+				ExcludedWeekdays: &ListWeekday{AsPtr(time.Saturday), AsPtr(time.Sunday)},
+				TaskDayTypes:     DayTypeMap{"vacation": VACATION_DAY},
+			}
+			entries, err := HandleTogglDetailedExportFile(config, strings.NewReader(tt.input))
+			// Days are combined after importing, as with all import files
+			var arr *ListSingleEntry
+			if err == nil {
+				arr, err = AggregateDays(config, entries)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(*arr) != len(tt.days) {
+				t.Fatalf("got %v days, want %v", len(*arr), len(tt.days))
+			}
+			for i, want := range tt.days {
+				got := (*arr)[i]
+				if !got.date.Equal(want.date) || got.duration != want.duration || got.dtype != want.dtype {
+					t.Errorf("day %v: got %s %v (%v), want %s %v (%v)", i, got.date.Format("02.01.2006"), got.duration, got.dtype, want.date.Format("02.01.2006"), want.duration, want.dtype)
+				}
+			}
+		})
+	}
+}
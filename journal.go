@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Punch clock commands, each appends at most one event into the journal
+// CONSTANT READONLY
+var JournalCommandMapping = JournalCommandMap{
+	CMD_START_STR:  JournalStart,
+	CMD_STOP_STR:   JournalStop,
+	CMD_BREAK_STR:  JournalBreak,
+	CMD_STATUS_STR: JournalStatus,
+}
+
+// Journal path from flag or config file, defaults next to config file
+// Config file is read only for the journal path, other keys are not validated
+func JournalFilePath(args *Args) (*string, error) {
+	configPath, err := ConfigFilePath(args)
+	if err != nil {
+		return nil, err
+	}
+	base := filepath.Dir(configPath)
+
+	v := args.Overrides[CNF_JOURNAL_PATH_STR]
+	if v == nil {
+		// Default config file may be omitted for punch clock commands
+		if _, err = os.Stat(configPath); err == nil || args.ConfigPath != nil {
+			configMapping, _, err := ReadConfigFile(&configPath)
+			if err != nil {
+				return nil, err
+			}
+			v = configMapping[CNF_JOURNAL_PATH_STR]
+		}
+	}
+	if v == nil {
+		return AsPtr(filepath.Join(base, JOURNAL_FILE)), nil
+	}
+	return ResolveNewFilePath(v, &base), nil
+}
+
+// Replay journal lines into work sessions
+// Line: <RFC3339 timestamp> <start|stop|break|resume>
+// Last session is left open if not stopped
+// Empty lines and lines starting with # are skipped
+func ReplayJournal(reader io.Reader) (sessions ListJournalSession, err error) {
+	scanner := bufio.NewScanner(reader)
+
+	var line Line = 0
+	var last time.Time
+
+	sessions = make(ListJournalSession, 0, 1024)
+	var open *JournalSession
+
+	for scanner.Scan() {
+		line++
+
+		rawstr := strings.TrimSpace(scanner.Text())
+		if rawstr == "" || strings.HasPrefix(rawstr, "#") {
+			continue
+		}
+
+		cols := strings.Fields(rawstr)
+		if len(cols) != 2 {
+			fmt.Printf("ERROR: Row: %v: Expected timestamp and event. Value: '%s'. Double check journal file.\n", line, rawstr)
+			return nil, errors.New("invalid line in journal")
+		}
+
+		t, err := time.Parse(time.RFC3339, cols[0])
+		if err != nil {
+			return nil, ErrorParse(line, AsPtr((Column)(0)), &cols[0], err)
+		}
+		if t.Before(last) {
+			return nil, ErrorParse(line, AsPtr((Column)(0)), &cols[0], errors.New("timestamp before previous event"))
+		}
+		last = t
+
+		// Event has to be valid in the current state
+		event := strings.ToLower(cols[1])
+		valid := false
+		switch event {
+		case JOURNAL_START:
+			if open == nil {
+				open = &JournalSession{start: t, line: line}
+				sessions = append(sessions, open)
+				valid = true
+			}
+		case JOURNAL_BREAK:
+			if open != nil && open.breakStart == nil {
+				open.breakStart = AsPtr(t)
+				valid = true
+			}
+		case JOURNAL_RESUME:
+			if open != nil && open.breakStart != nil {
+				open.breaks += t.Sub(*open.breakStart)
+				open.breakStart = nil
+				valid = true
+			}
+		case JOURNAL_STOP:
+			if open != nil {
+				// Stopping during break ends the break too
+				if open.breakStart != nil {
+					open.breaks += t.Sub(*open.breakStart)
+					open.breakStart = nil
+				}
+				open.end = AsPtr(t)
+				open = nil
+				valid = true
+			}
+		default:
+			return nil, ErrorParse(line, AsPtr((Column)(1)), &cols[1], errors.New("unknown journal event"))
+		}
+		if !valid {
+			return nil, ErrorParse(line, AsPtr((Column)(1)), &cols[1], errors.New("event not allowed in current state"))
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		fmt.Println("ERROR: Failed to read line from journal file. Err:", err.Error())
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// Open and replay journal, missing journal has no sessions
+func ReadJournal(path *string) (ListJournalSession, error) {
+	f, err := os.Open(*path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ListJournalSession{}, nil
+	}
+	if err != nil {
+		fmt.Printf("ERROR: Failed to open journal (path: %s) Err: %s\n", *path, err.Error())
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReplayJournal(bufio.NewReader(f))
+}
+
+// Append single event line into journal, created if missing
+func AppendJournal(path *string, now time.Time, event string) error {
+	f, err := os.OpenFile(*path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		fmt.Printf("ERROR: Failed to open journal for writing (path: %s) Err: %s\n", *path, err.Error())
+		return err
+	}
+
+	_, err = fmt.Fprintf(f, "%s %s\n", now.Format(time.RFC3339), event)
+	if err != nil {
+		f.Close()
+		fmt.Println("ERROR: Failed to write into journal file:", err.Error())
+		return err
+	}
+
+	return f.Close()
+}
+
+// Last session if it is still running
+func openSession(sessions ListJournalSession) *JournalSession {
+	if len(sessions) <= 0 || sessions[len(sessions)-1].end != nil {
+		return nil
+	}
+	return sessions[len(sessions)-1]
+}
+
+// Worked time split into days, open session and break counted until now
+func SessionEntries(s *JournalSession, now time.Time) ListTimeEntry {
+	end, breaks := now, s.breaks
+	if s.end != nil {
+		end = *s.end
+	}
+	if s.breakStart != nil {
+		breaks += end.Sub(*s.breakStart)
+	}
+	return SessionTimeEntries(s.start, end, breaks, s.line)
+}
+
+// Start working, resumes if on break
+func JournalStart(path *string, sessions ListJournalSession, now time.Time) error {
+	open := openSession(sessions)
+	if open != nil && open.breakStart != nil {
+		fmt.Println("Resumed work after break at", now.Format(SHORT_TIME_LAYOUT))
+		return AppendJournal(path, now, JOURNAL_RESUME)
+	}
+	if open != nil {
+		fmt.Println("ERROR: Already working since", open.start.Format(time.DateTime))
+		return errors.New("session already started")
+	}
+	fmt.Println("Started work at", now.Format(SHORT_TIME_LAYOUT))
+	return AppendJournal(path, now, JOURNAL_START)
+}
+
+// Stop working, ends also a running break
+func JournalStop(path *string, sessions ListJournalSession, now time.Time) error {
+	open := openSession(sessions)
+	if open == nil {
+		fmt.Println("ERROR: Not working, nothing to stop.")
+		return errors.New("no session started")
+	}
+	if err := AppendJournal(path, now, JOURNAL_STOP); err != nil {
+		return err
+	}
+	open.end = AsPtr(now)
+	var worked Hours
+	for _, e := range SessionEntries(open, now) {
+		worked += e.duration
+	}
+	fmt.Printf("Stopped work at %s. Session worked hours: %s\n", now.Format(SHORT_TIME_LAYOUT), worked)
+	return nil
+}
+
+// Start a break, or end the running break
+func JournalBreak(path *string, sessions ListJournalSession, now time.Time) error {
+	open := openSession(sessions)
+	if open == nil {
+		fmt.Println("ERROR: Not working, cannot start a break.")
+		return errors.New("no session started")
+	}
+	if open.breakStart != nil {
+		fmt.Println("Resumed work after break at", now.Format(SHORT_TIME_LAYOUT))
+		return AppendJournal(path, now, JOURNAL_RESUME)
+	}
+	fmt.Println("Started break at", now.Format(SHORT_TIME_LAYOUT))
+	return AppendJournal(path, now, JOURNAL_BREAK)
+}
+
+// Print current state and hours worked today
+func JournalStatus(path *string, sessions ListJournalSession, now time.Time) error {
+	open := openSession(sessions)
+	switch {
+	case open == nil && len(sessions) <= 0:
+		fmt.Println("Not working. Journal has no sessions yet.")
+	case open == nil:
+		fmt.Println("Not working. Last session stopped at", sessions[len(sessions)-1].end.Format(time.DateTime))
+	case open.breakStart != nil:
+		fmt.Printf("On break since %s. Working since %s.\n", open.breakStart.Format(SHORT_TIME_LAYOUT), open.start.Format(time.DateTime))
+	default:
+		fmt.Println("Working since", open.start.Format(time.DateTime))
+	}
+
+	today := DateKey(now)
+	var worked Hours
+	for _, s := range sessions {
+		for _, e := range SessionEntries(s, now) {
+			if e.date.Equal(today) {
+				worked += e.duration
+			}
+		}
+	}
+	fmt.Println("Worked hours today:", worked)
+	return nil
+}
+
+// Run punch clock command given as first argument, returns the exit code
+func RunJournalCommand(args *Args) int {
+	path, err := JournalFilePath(args)
+	if err != nil {
+		fmt.Println("ERROR: Could not resolve journal file path. Err:", err)
+		return EXIT_CONFIG
+	}
+
+	sessions, err := ReadJournal(path)
+	if err != nil {
+		fmt.Printf("ERROR: Could not read journal file (path: %s). Err: %s\n", *path, err)
+		return EXIT_FAILURE
+	}
+
+	if err = JournalCommandMapping[*args.Command](path, sessions, time.Now().Truncate(time.Second)); err != nil {
+		return EXIT_FAILURE
+	}
+
+	return EXIT_OK
+}
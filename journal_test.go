@@ -0,0 +1,133 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReplayJournal(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		sessions int
+		open     bool
+		breaks   time.Duration
+		wantErr  bool
+	}{
+		{
+			name:     "closed sessions with break",
+			input:    "# comment\n2023-10-02T08:00:00Z start\n2023-10-02T12:00:00Z break\n2023-10-02T12:30:00Z resume\n2023-10-02T16:00:00Z stop\n\n2023-10-03T08:00:00Z start\n2023-10-03T16:00:00Z stop\n",
+			sessions: 2,
+		},
+		{
+			name:     "stop during break",
+			input:    "2023-10-02T08:00:00Z start\n2023-10-02T15:00:00Z break\n2023-10-02T15:45:00Z STOP\n",
+			sessions: 1,
+			breaks:   45 * time.Minute,
+		},
+		{
+			name:     "open session",
+			input:    "2023-10-02T08:00:00Z start\n2023-10-02T12:00:00Z break\n",
+			sessions: 1,
+			open:     true,
+		},
+		{
+			name:    "start twice",
+			input:   "2023-10-02T08:00:00Z start\n2023-10-02T09:00:00Z start\n",
+			wantErr: true,
+		},
+		{
+			name:    "resume without break",
+			input:   "2023-10-02T08:00:00Z start\n2023-10-02T09:00:00Z resume\n",
+			wantErr: true,
+		},
+		{
+			name:    "timestamp out of order",
+			input:   "2023-10-02T08:00:00Z start\n2023-10-02T07:00:00Z stop\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown event",
+			input:   "2023-10-02T08:00:00Z lunch\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing event",
+			input:   "2023-10-02T08:00:00Z\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessions, err := ReplayJournal(strings.NewReader(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(sessions) != tt.sessions {
+				t.Fatalf("got %v sessions, want %v", len(sessions), tt.sessions)
+			}
+			last := sessions[len(sessions)-1]
+			if (last.end == nil) != tt.open {
+				t.Errorf("got open %v, want %v", last.end == nil, tt.open)
+			}
+			if tt.breaks > 0 && last.breaks != tt.breaks {
+				t.Errorf("got breaks %v, want %v", last.breaks, tt.breaks)
+			}
+		})
+	}
+}
+
+func TestJournalCommands(t *testing.T) {
+	path := AsPtr(filepath.Join(t.TempDir(), JOURNAL_FILE))
+	now, _ := time.Parse(time.RFC3339, "2023-10-02T08:00:00Z")
+
+	steps := []struct {
+		command string
+		minutes int
+		wantErr bool
+	}{
+		{command: CMD_STOP_STR, wantErr: true},
+		{command: CMD_START_STR},
+		{command: CMD_START_STR, minutes: 10, wantErr: true},
+		{command: CMD_BREAK_STR, minutes: 240},
+		{command: CMD_START_STR, minutes: 270},
+		{command: CMD_STATUS_STR, minutes: 300},
+		{command: CMD_BREAK_STR, minutes: 400},
+		{command: CMD_STOP_STR, minutes: 420},
+	}
+
+	for i, step := range steps {
+		sessions, err := ReadJournal(path)
+		if err != nil {
+			t.Fatalf("step %v: unexpected read error: %v", i, err)
+		}
+		err = JournalCommandMapping[step.command](path, sessions, now.Add(time.Duration(step.minutes)*time.Minute))
+		if (err != nil) != step.wantErr {
+			t.Fatalf("step %v (%s): got error %v, want error %v", i, step.command, err, step.wantErr)
+		}
+	}
+
+	sessions, err := ReadJournal(path)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].end == nil {
+		t.Fatalf("got %v sessions, want one closed session", len(sessions))
+	}
+	var worked Hours
+	for _, e := range SessionEntries(sessions[0], now) {
+		worked += e.duration
+	}
+	if worked != 617 {
+		t.Errorf("got worked %v, want 6.50", worked)
+	}
+}
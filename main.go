@@ -2,53 +2,83 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 )
 
-func ConsoleBlock() {
+// Wait for user input after a run
+// Returns whether to rerun and if the rerun should export into file
+func ConsoleBlock() (rerun bool, export bool) {
 	reader := bufio.NewReader(os.Stdin)
 	fmt.Println("Program Complete.")
 	fmt.Println("Hit Enter to Rerun.. 'e' to export to file or 'q' to quit")
-	r, _, _ := reader.ReadLine()
+	r, _, err := reader.ReadLine()
+	// Nothing more to read (e.g. stdin closed), cannot rerun
+	if err != nil {
+		return false, false
+	}
 	rs := string(r)
 	if len(rs) > 0 {
 		if rs[0] == 'q' {
-			return
+			return false, false
 		}
 		if rs[0] == 'e' {
-			oper(true)
-			return
+			return true, true
 		}
 	}
-	oper(false)
+	return true, false
 }
 
 func main() {
-	oper(false)
-}
+	args, err := ParseArgs(os.Args[1:])
+	if err != nil {
+		os.Exit(EXIT_CONFIG)
+	}
+
+	// Punch clock commands run once without config validation
+	if args.Command != nil {
+		os.Exit(RunJournalCommand(args))
+	}
 
-func oper(export bool) {
-	// Console holder
-	// Deferred, will be called when this func returns
-	defer ConsoleBlock()
+	// Non-interactive, single run for scripts
+	if args.Once {
+		os.Exit(oper(args, args.Export))
+	}
+
+	// Console holder, rerun until user quits
+	export := false
+	for {
+		_ = oper(args, export)
+		rerun, exp := ConsoleBlock()
+		if !rerun {
+			return
+		}
+		export = exp
+	}
+}
 
-	config, err := ParseValidateConfig()
+// Single program run, returns the exit code
+func oper(args *Args, export bool) int {
+	config, err := ParseValidateConfig(args)
 
 	if err != nil {
+		configPath, _ := ConfigFilePath(args)
 		fmt.Printf("ERROR: Could not open or parse config file or config is invalid. "+
-			"Double check exists file: %s in current directory. Err: %s\n", CONFIG_FILE, err)
-		return
+			"Double check config file: %s. Err: %s\n", configPath, err)
+		return EXIT_CONFIG
 	}
 
 	fmt.Println("Config read OK.")
 	fmt.Println()
 
-	entries, entries2, err := ParseImportFile(config)
+	data, err := ParseImportFile(config)
 
 	if err != nil {
 		fmt.Println("Failed to parse input file. Err:", err)
-		return
+		return EXIT_FAILURE
 	}
 
 	fmt.Println("Import file parsed OK. Note any errors above.")
@@ -56,10 +86,10 @@ func oper(export bool) {
 
 	// Share some readonly variables
 	global := &Common{
-		weeklyHours: config.DailyHours * float64((func() uint8 {
+		weeklyHours: config.DailyHours * Hours((func() uint8 {
 			var days uint8
 			for _, v := range ConfigWeekdayMapping {
-				if config.ExcludedWeekdays != nil && !ValueInArray(v, config.ExcludedWeekdays) {
+				if config.ExcludedWeekdays == nil || !ValueInArray(v, config.ExcludedWeekdays) {
 					days += 1
 				}
 			}
@@ -76,66 +106,72 @@ func oper(export bool) {
 	}
 	fmt.Println("Daily Work Hours:", config.DailyHours)
 	fmt.Println("Weekly Work Hours:", global.weeklyHours)
-	fmt.Println("Running in mode:", *OperationModeRevMapping[config.Mode])
-	fmt.Println()
-
-	switch config.Mode {
-	case CHECK_MODE:
-		switch config.IfType {
-		case CUSTOM_FILE:
-			ExportCustomFile(config, global, entries)
-		default:
-			fmt.Println("ERROR: Handling not defined for given input file type.")
-			return
-		}
-	case REPORT_MODE:
-		switch config.IfType {
-		case CLOCKIFY_FILE:
-			if len(*entries2) <= 0 {
-				fmt.Println("ERROR: No entries to report from input file. Double check input file correct.")
-				return
+	if config.Contracts != nil && len(*config.Contracts) > 0 {
+		fmt.Println("Contracts (daily hours):")
+		for _, c := range *config.Contracts {
+			from, to, wd := "", "", "all days"
+			if c.from != nil {
+				from = c.from.Format(*config.DateParseLayout)
 			}
-			// If not exporting, write to stdout
-			if !export {
-				fmt.Println("Listing collected days:")
-				fmt.Println()
-				err = ExportClockifyReport(config, global, entries2, fmt.Printf, fmt.Println)
-				if err != nil {
-					fmt.Println("ERROR: Failed to display report file:", err.Error())
-					return
-				}
-			} else {
-				// Then write into export file
-				fmt.Println("Exporting report into file..")
-				if config.ExportFilePath == nil {
-					fmt.Println("ERROR: Report export requested but export dir not defined in config.")
-					return
-				}
-				outFile, err := os.Create(*config.ExportFilePath)
-				if err != nil {
-					fmt.Printf("ERROR: Could not open export file: %s Error: %s\n", *config.ExportFilePath, err.Error())
-					return
-				}
-				defer outFile.Close()
-				printlnF := func(a ...any) (n int, err error) {
-					return outFile.WriteString(fmt.Sprintln(a...))
-				}
-				printfF := func(format string, a ...any) (n int, err error) {
-					return outFile.WriteString(fmt.Sprintf(format, a...))
-				}
-				err = ExportClockifyReport(config, global, entries2, printfF, printlnF)
-				if err != nil {
-					fmt.Println("ERROR: Failed to export report file:", err.Error())
-					return
-				}
-				fmt.Println("Report exported OK into file:", *config.ExportFileName)
+			if c.to != nil {
+				to = c.to.Format(*config.DateParseLayout)
 			}
-		default:
-			fmt.Println("ERROR: Handling not defined for given input file type.")
-			return
+			if c.weekday != nil {
+				wd = c.weekday.String()
+			}
+			fmt.Printf("  %s - %s (%s): %s\n", from, to, wd, c.hours)
 		}
-	default:
+	}
+	if config.Holidays != nil {
+		fmt.Printf("Holidays: built-in calendar: %v, user defined days: %v\n", config.Holidays.rules != nil, len(config.Holidays.custom))
+	}
+	fmt.Println("Running in mode:", *OperationModeRevMapping[config.Mode])
+	fmt.Println()
+
+	handler, ok := ModeHandlerMapping[config.Mode]
+	if !ok {
 		fmt.Println("ERROR: Unknown operation mode set. Cannot proceed.")
-		return
+		return EXIT_FAILURE
+	}
+
+	err = handler.run(config, global, data, export)
+	if err != nil {
+		return EXIT_FAILURE
+	}
+
+	return EXIT_OK
+}
+
+// Run the report either to stdout or into the named file in export dir
+func outputReport(config *Config, export bool, fileName *string, report func(w io.Writer) error) error {
+	// If not exporting, write to stdout
+	if !export {
+		err := report(os.Stdout)
+		if err != nil {
+			fmt.Println("ERROR: Failed to display report file:", err.Error())
+			return err
+		}
+		return nil
+	}
+
+	// Then write into export file
+	fmt.Println("Exporting report into file..")
+	if config.ExportDir == nil {
+		fmt.Println("ERROR: Report export requested but export dir not defined in config.")
+		return errors.New("export dir not defined")
+	}
+	filePath := filepath.Join(*config.ExportDir, *fileName)
+	outFile, err := os.Create(filePath)
+	if err != nil {
+		fmt.Printf("ERROR: Could not open export file: %s Error: %s\n", filePath, err.Error())
+		return err
+	}
+	defer outFile.Close()
+	err = report(outFile)
+	if err != nil {
+		fmt.Println("ERROR: Failed to export report file:", err.Error())
+		return err
 	}
+	fmt.Println("Report exported OK into file:", *fileName)
+	return nil
 }
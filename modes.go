@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Handlers of each operation mode
+// Import kinds are validated against the file type in config
+// CONSTANT READONLY
+var ModeHandlerMapping = ModeHandlerMap{
+	CHECK_MODE:    {kinds: []ImportKind{WEEK_IMPORT}, run: RunCheckMode},
+	FIX_MODE:      {kinds: []ImportKind{WEEK_IMPORT}, run: RunFixMode},
+	GENERATE_MODE: {kinds: []ImportKind{DAY_IMPORT}, run: RunGenerateMode},
+	REPORT_MODE:   {kinds: []ImportKind{WEEK_IMPORT, DAY_IMPORT}, run: RunReportMode},
+}
+
+func RunCheckMode(config *Config, global *Common, data *ImportData, export bool) error {
+	result := CheckCustomEntries(config, global, data.weeks)
+	err := RunExporters(config, global, &ExportData{check: result}, export)
+	if err != nil {
+		return err
+	}
+	if result.errorCount > 0 {
+		return errors.New("check found discrepancies")
+	}
+	return nil
+}
+
+func RunFixMode(config *Config, global *Common, data *ImportData, export bool) error {
+	return FixCustomFile(config, global, data.weeks)
+}
+
+func RunGenerateMode(config *Config, global *Common, data *ImportData, export bool) error {
+	if len(*data.days) <= 0 {
+		fmt.Println("ERROR: No entries to generate from input file. Double check input file correct.")
+		return errors.New("no entries")
+	}
+	weeks := AggregateWeeks(config, data.days)
+	return outputReport(config, export, ReportFileName(AsPtr("manual"), "txt"), func(w io.Writer) error {
+		return ExportCustomFormat(weeks, w)
+	})
+}
+
+func RunReportMode(config *Config, global *Common, data *ImportData, export bool) error {
+	if data.weeks != nil {
+		if len(*data.weeks) <= 0 {
+			fmt.Println("ERROR: No entries to report from input file. Double check input file correct.")
+			return errors.New("no entries")
+		}
+		if config.ReportFrom != nil || config.ReportTo != nil {
+			fmt.Println("WARNING: Report range is supported for daily entries only. Range ignored.")
+		}
+		fmt.Println("Listing collected weeks:")
+		fmt.Println()
+		return RunExporters(config, global, &ExportData{weeks: data.weeks}, export)
+	}
+
+	if len(*data.days) <= 0 {
+		fmt.Println("ERROR: No entries to report from input file. Double check input file correct.")
+		return errors.New("no entries")
+	}
+
+	// Closed periods carry their balance over to later runs
+	var snapshots ListSnapshot
+	if config.SnapshotPath != nil {
+		var err error
+		snapshots, err = ReadSnapshots(config.SnapshotPath)
+		if err != nil {
+			return err
+		}
+		ApplySnapshot(config, snapshots, data.days)
+	}
+
+	report := BuildReport(config, data.days)
+
+	// Balance calculated from all days, only days in range are shown
+	view := FilterReport(config, report)
+	if config.ReportFrom != nil || config.ReportTo != nil {
+		from, to := "", ""
+		if config.ReportFrom != nil {
+			from = config.ReportFrom.Format(*config.DateParseLayout)
+		}
+		if config.ReportTo != nil {
+			to = config.ReportTo.Format(*config.DateParseLayout)
+		}
+		fmt.Printf("Report range: %s - %s\n", from, to)
+		if len(view.days) <= 0 {
+			fmt.Println("ERROR: No imported days within report range. Double check report range.")
+			return errors.New("no entries in range")
+		}
+	}
+
+	fmt.Println("Listing collected days:")
+	fmt.Println()
+	err := RunExporters(config, global, &ExportData{report: view}, export)
+	if err != nil {
+		return err
+	}
+
+	if config.SnapshotPath != nil {
+		snapshots, changed := RecordSnapshots(config, snapshots, report, time.Now())
+		if changed {
+			return WriteSnapshots(config.SnapshotPath, snapshots)
+		}
+	}
+	return nil
+}
+
+// Write the data in every export format selected in config
+// Formats not supporting the data are errors, checked before writing anything
+func RunExporters(config *Config, global *Common, data *ExportData, export bool) error {
+	files := make(ListExportFile, 0, len(*config.ExportFormats))
+	for _, name := range *config.ExportFormats {
+		f := ExporterRegistry[*name].Files(config, global, data)
+		if f == nil {
+			fmt.Printf("ERROR: Export format (%s) not supported in mode (%s) with import file type (%s).\n", *name, *OperationModeRevMapping[config.Mode], *config.FileType)
+			return errors.New("unsupported export format")
+		}
+		files = append(files, f...)
+	}
+
+	for _, f := range files {
+		err := outputReport(config, export, ReportFileName(f.suffix, f.ext), f.write)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
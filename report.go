@@ -0,0 +1,112 @@
+package main
+
+// Calculate the day and week values of the report from single day entries
+// Shared by all report output formats
+func BuildReport(config *Config, entries *ListSingleEntry) *Report {
+	report := &Report{
+		days:  make(ListReportDay, 0, len(*entries)),
+		weeks: make(ListReportWeek, 0),
+	}
+
+	// Keep track of some variables
+	var balance Hours = 0
+	if config.InitialBalance != nil {
+		balance += *config.InitialBalance
+	}
+	report.initial = balance
+
+	var week *ReportWeek
+
+	for i, e := range *entries {
+		// Start a new week whenever the week number changes
+		year, wk := e.date.ISOWeek()
+		if week == nil || week.week != wk || week.year != year {
+			week = &ReportWeek{
+				days:  make(ListReportDay, 0, 7),
+				index: len(report.weeks) + 1,
+				year:  year,
+				week:  wk,
+				start: e.date,
+			}
+			report.weeks = append(report.weeks, week)
+		}
+
+		// Usually only mon-fri has daily hours limit
+		// Other days, holidays and absences hours are counted as additional hours
+		day := &ReportDay{
+			entry:    e,
+			index:    i,
+			required: DayRequiredHours(config, e.date, e.dtype),
+		}
+		if config.Holidays != nil {
+			day.holiday = config.Holidays.Lookup(e.date)
+		}
+		day.diff = e.duration - day.required
+
+		balance += day.diff
+		day.balance = balance
+
+		week.days = append(week.days, day)
+		week.end = e.date
+		week.worked += e.duration
+		week.required += day.required
+		week.diff += day.diff
+		week.balance = balance
+
+		report.days = append(report.days, day)
+	}
+
+	report.balance = balance
+
+	return report
+}
+
+// Days of the report range only, balances stay as calculated from the full history
+// Weeks are limited to their days in range, initial balance is the balance before the range
+func FilterReport(config *Config, report *Report) *Report {
+	if config.ReportFrom == nil && config.ReportTo == nil {
+		return report
+	}
+
+	out := &Report{
+		days:    make(ListReportDay, 0, len(report.days)),
+		weeks:   make(ListReportWeek, 0),
+		initial: report.initial,
+		balance: report.initial,
+	}
+
+	for _, w := range report.weeks {
+		var week *ReportWeek
+		for _, d := range w.days {
+			if config.ReportFrom != nil && d.entry.date.Before(*config.ReportFrom) {
+				out.initial = d.balance
+				out.balance = d.balance
+				continue
+			}
+			if config.ReportTo != nil && d.entry.date.After(*config.ReportTo) {
+				break
+			}
+			if week == nil {
+				week = &ReportWeek{
+					days:  make(ListReportDay, 0, len(w.days)),
+					index: w.index,
+					year:  w.year,
+					week:  w.week,
+					start: d.entry.date,
+				}
+				out.weeks = append(out.weeks, week)
+			}
+			week.days = append(week.days, d)
+			week.end = d.entry.date
+			week.worked += d.entry.duration
+			week.required += d.required
+			week.diff += d.diff
+			week.balance = d.balance
+
+			out.days = append(out.days, d)
+			out.balance = d.balance
+		}
+	}
+
+	return out
+}
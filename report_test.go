@@ -0,0 +1,135 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// Single day entries from date and worked hours pairs
+func reportEntries(days ...any) *ListSingleEntry {
+	arr := make(ListSingleEntry, 0, len(days)/2)
+	for i := 0; i+1 < len(days); i += 2 {
+		d, _ := time.Parse("02.01.2006", days[i].(string))
+		arr = append(arr, &SingleEntry{date: d, duration: Hours(days[i+1].(int))})
+	}
+	return &arr
+}
+
+func TestBuildReport(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries *ListSingleEntry
+		weeks   []Hours
+		balance Hours
+	}{
+		{
+			name:    "single week",
+			entries: reportEntries("02.10.2023", 800, "03.10.2023", 725, "07.10.2023", 100),
+			weeks:   []Hours{75 + 0 + 100},
+			balance: 150 + 175,
+		},
+		{
+			name:    "weeks split by iso week",
+			entries: reportEntries("06.10.2023", 725, "08.10.2023", 0, "09.10.2023", 625),
+			weeks:   []Hours{0, -100},
+			balance: 150 - 100,
+		},
+		{
+			name:    "holiday requires none",
+			entries: reportEntries("06.12.2023", 0),
+			weeks:   []Hours{0},
+			balance: 150,
+		},
+		{
+			name:    "no entries",
+			entries: reportEntries(),
+			weeks:   []Hours{},
+			balance: 150,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				DailyHours:       725,
+				ExcludedWeekdays: &ListWeekday{AsPtr(time.Saturday), AsPtr(time.Sunday)},
+				Holidays:         NewHolidayCalendar(FinnishHolidays),
+				InitialBalance:   AsPtr[Hours](150),
+			}
+			ApplyDayTypes(config, tt.entries)
+
+			report := BuildReport(config, tt.entries)
+			if len(report.days) != len(*tt.entries) {
+				t.Errorf("got %v days, want %v", len(report.days), len(*tt.entries))
+			}
+			if len(report.weeks) != len(tt.weeks) {
+				t.Fatalf("got %v weeks, want %v", len(report.weeks), len(tt.weeks))
+			}
+			for i, want := range tt.weeks {
+				if wk := report.weeks[i]; wk.diff != want || wk.index != i+1 {
+					t.Errorf("week %v: got %v diff %v, want diff %v", i, wk.index, wk.diff, want)
+				}
+			}
+			if report.balance != tt.balance {
+				t.Errorf("got balance %v, want %v", report.balance, tt.balance)
+			}
+		})
+	}
+}
+
+func TestFilterReport(t *testing.T) {
+	date := func(s string) *time.Time {
+		d, _ := time.Parse("02.01.2006", s)
+		return &d
+	}
+
+	tests := []struct {
+		name    string
+		from    *time.Time
+		to      *time.Time
+		days    int
+		weeks   []Hours
+		initial Hours
+		balance Hours
+	}{
+		{name: "no range", days: 6, weeks: []Hours{75, -100}, initial: 150, balance: 125},
+		{name: "open end", from: date("04.10.2023"), days: 4, weeks: []Hours{0, -100}, initial: 150 + 75, balance: 125},
+		{name: "open start", to: date("06.10.2023"), days: 3, weeks: []Hours{75}, initial: 150, balance: 150 + 75},
+		{name: "within week", from: date("03.10.2023"), to: date("09.10.2023"), days: 3, weeks: []Hours{0, 0}, initial: 150 + 75, balance: 150 + 75},
+		{name: "outside of days", from: date("01.11.2023"), days: 0, weeks: []Hours{}, initial: 125, balance: 125},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				DailyHours:       725,
+				ExcludedWeekdays: &ListWeekday{AsPtr(time.Saturday), AsPtr(time.Sunday)},
+				InitialBalance:   AsPtr[Hours](150),
+				ReportFrom:       tt.from,
+				ReportTo:         tt.to,
+			}
+			entries := reportEntries("02.10.2023", 800, "03.10.2023", 725, "06.10.2023", 725,
+				"09.10.2023", 725, "10.10.2023", 625, "11.10.2023", 725)
+			ApplyDayTypes(config, entries)
+
+			report := FilterReport(config, BuildReport(config, entries))
+			if len(report.days) != tt.days {
+				t.Errorf("got %v days, want %v", len(report.days), tt.days)
+			}
+			if len(report.weeks) != len(tt.weeks) {
+				t.Fatalf("got %v weeks, want %v", len(report.weeks), len(tt.weeks))
+			}
+			for i, want := range tt.weeks {
+				if wk := report.weeks[i]; wk.diff != want {
+					t.Errorf("week %v: got diff %v, want %v", i, wk.diff, want)
+				}
+			}
+			if report.initial != tt.initial {
+				t.Errorf("got initial %v, want %v", report.initial, tt.initial)
+			}
+			if report.balance != tt.balance {
+				t.Errorf("got balance %v, want %v", report.balance, tt.balance)
+			}
+		})
+	}
+}
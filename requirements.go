@@ -0,0 +1,91 @@
+package main
+
+import (
+	"time"
+)
+
+// Find the contract in effect for the date
+// Later contracts take precedence over earlier overlapping ones
+func (contracts *ListContract) Match(date time.Time, weekdaySpecific bool) *Contract {
+	if contracts == nil {
+		return nil
+	}
+	key := DateKey(date)
+	for i := len(*contracts) - 1; i >= 0; i-- {
+		c := (*contracts)[i]
+		if (c.weekday != nil) != weekdaySpecific {
+			continue
+		}
+		if c.weekday != nil && *c.weekday != key.Weekday() {
+			continue
+		}
+		if c.from != nil && key.Before(*c.from) {
+			continue
+		}
+		if c.to != nil && key.After(*c.to) {
+			continue
+		}
+		return c
+	}
+	return nil
+}
+
+// Required work hours for a single date
+// Weekday specific contracts override excluded weekdays
+// Holidays require none, unless holiday defines reduced hours
+func RequiredHours(config *Config, date time.Time) (required Hours) {
+	if c := config.Contracts.Match(date, true); c != nil {
+		required = c.hours
+	} else if config.ExcludedWeekdays != nil && ValueInArray(AsPtr(date.Weekday()), config.ExcludedWeekdays) {
+		return 0
+	} else if c := config.Contracts.Match(date, false); c != nil {
+		required = c.hours
+	} else {
+		required = config.DailyHours
+	}
+	if config.Holidays != nil {
+		if h := config.Holidays.Lookup(date); h != nil {
+			if h.hours != nil {
+				return min(*h.hours, required)
+			}
+			return 0
+		}
+	}
+	return required
+}
+
+// Required work hours for a day of given type
+// Absence days require none, flex day off consumes balance like a missed work day
+// Holidays require only the reduced hours defined in holiday calendar
+func DayRequiredHours(config *Config, date time.Time, dtype DayType) Hours {
+	switch dtype {
+	case WORK_DAY, FLEX_OFF_DAY:
+		return RequiredHours(config, date)
+	case HOLIDAY_DAY:
+		if config.Holidays != nil {
+			if h := config.Holidays.Lookup(date); h != nil && h.hours != nil {
+				return RequiredHours(config, date)
+			}
+		}
+		return 0
+	default:
+		return 0
+	}
+}
+
+// Required work hours for all days of a week entry
+// Plain weekly hours if the dates of the entry are unknown
+// Day types annotated in the entry comment take precedence
+func WeekRequiredHours(config *Config, global *Common, e *WeekEntry) (required Hours) {
+	if e.start.IsZero() {
+		return global.weeklyHours
+	}
+	for d := e.start; !d.After(e.end); d = d.AddDate(0, 0, 1) {
+		dtype, ok := e.dayTypes[d]
+		if !ok {
+			dtype = LookupDayType(config, d, WORK_DAY)
+		}
+		required += DayRequiredHours(config, d, dtype)
+	}
+	return
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeekRequiredHours(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return d
+	}
+
+	tests := []struct {
+		name  string
+		start string
+		end   string
+		want  Hours
+	}{
+		{name: "dates unknown", want: 3625},
+		{name: "full week", start: "2023-10-02", end: "2023-10-08", want: 3625},
+		{name: "week starting mid-week", start: "2023-10-04", end: "2023-10-08", want: 2175},
+		{name: "holiday", start: "2023-12-04", end: "2023-12-10", want: 2900},
+		{name: "reduced hours holiday", start: "2024-04-29", end: "2024-05-05", want: 725 + 400 + 725 + 725},
+		{name: "holiday on excluded weekday", start: "2024-06-17", end: "2024-06-23", want: 2900},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				DailyHours:       725,
+				ExcludedWeekdays: &ListWeekday{AsPtr(time.Saturday), AsPtr(time.Sunday)},
+				Holidays:         NewHolidayCalendar(FinnishHolidays),
+			}
+			config.Holidays.custom[date("2024-04-30")] = &Holiday{date: date("2024-04-30"), name: AsPtr("Vappuaatto"), hours: AsPtr[Hours](400)}
+			global := &Common{weeklyHours: 3625}
+
+			e := &WeekEntry{trange: AsPtr(tt.name)}
+			if tt.start != "" {
+				e.start, e.end = date(tt.start), date(tt.end)
+			}
+			if got := WeekRequiredHours(config, global, e); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDayRequiredHours(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return d
+	}
+
+	tests := []struct {
+		name  string
+		date  string
+		dtype DayType
+		want  Hours
+	}{
+		{name: "work day", date: "2024-04-29", dtype: WORK_DAY, want: 725},
+		{name: "vacation", date: "2024-04-29", dtype: VACATION_DAY, want: 0},
+		{name: "sick", date: "2024-04-29", dtype: SICK_DAY, want: 0},
+		{name: "unpaid", date: "2024-04-29", dtype: UNPAID_DAY, want: 0},
+		{name: "flex day off", date: "2024-04-29", dtype: FLEX_OFF_DAY, want: 725},
+		{name: "holiday", date: "2024-05-01", dtype: HOLIDAY_DAY, want: 0},
+		{name: "holiday with reduced hours", date: "2024-04-30", dtype: HOLIDAY_DAY, want: 400},
+		{name: "holiday from absences", date: "2024-04-29", dtype: HOLIDAY_DAY, want: 0},
+		{name: "excluded weekday", date: "2024-05-04", dtype: WORK_DAY, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				DailyHours:       725,
+				ExcludedWeekdays: &ListWeekday{AsPtr(time.Saturday), AsPtr(time.Sunday)},
+				Holidays:         NewHolidayCalendar(FinnishHolidays),
+			}
+			config.Holidays.custom[date("2024-04-30")] = &Holiday{date: date("2024-04-30"), name: AsPtr("Vappuaatto"), hours: AsPtr[Hours](400)}
+
+			if got := DayRequiredHours(config, date(tt.date), tt.dtype); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
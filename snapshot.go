@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Last day of the snapshot period of the date
+func PeriodEnd(period SnapshotPeriod, date time.Time) time.Time {
+	if period == MONTH_PERIOD {
+		return time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(date.Year(), time.December, 31, 0, 0, 0, 0, time.UTC)
+}
+
+// Read snapshot file, missing file has no snapshots
+// Line: <YYYY-MM-DD> <closing balance>
+// Empty lines and lines starting with # are skipped
+func ReadSnapshots(path *string) (snapshots ListSnapshot, err error) {
+	snapshots = make(ListSnapshot, 0, 64)
+
+	f, err := os.Open(*path)
+	if errors.Is(err, os.ErrNotExist) {
+		return snapshots, nil
+	}
+	if err != nil {
+		fmt.Printf("ERROR: Failed to open snapshot file (path: %s) Err: %s\n", *path, err.Error())
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	var line Line = 0
+
+	for scanner.Scan() {
+		line++
+
+		rawstr := strings.TrimSpace(scanner.Text())
+		if rawstr == "" || strings.HasPrefix(rawstr, "#") {
+			continue
+		}
+
+		cols := strings.Fields(rawstr)
+		if len(cols) != 2 {
+			fmt.Printf("ERROR: Row: %v: Expected date and balance. Value: '%s'. Double check snapshot file.\n", line, rawstr)
+			return nil, errors.New("invalid line in snapshot file")
+		}
+		date, err := time.Parse(ISO_DATE_LAYOUT, cols[0])
+		if err != nil {
+			return nil, ErrorParse(line, AsPtr((Column)(0)), &cols[0], err)
+		}
+		balance, err := ParseHours(&cols[1])
+		if err != nil {
+			return nil, ErrorParse(line, AsPtr((Column)(1)), &cols[1], err)
+		}
+		snapshots = append(snapshots, &Snapshot{date: date, balance: balance})
+	}
+	if err = scanner.Err(); err != nil {
+		fmt.Println("ERROR: Failed to read line from snapshot file. Err:", err.Error())
+		return nil, err
+	}
+
+	slices.SortFunc(snapshots, func(a, b *Snapshot) int { return a.date.Compare(b.date) })
+
+	return snapshots, nil
+}
+
+// Rewrite snapshot file, earliest first
+func WriteSnapshots(path *string, snapshots ListSnapshot) error {
+	var sb strings.Builder
+	sb.WriteString("# Closing balances of report periods, updated by report runs\n")
+	for _, s := range snapshots {
+		sb.WriteString(fmt.Sprintf("%s %s\n", s.date.Format(ISO_DATE_LAYOUT), *PlusSignIfNecessary(s.balance)))
+	}
+
+	if err := os.WriteFile(*path, []byte(sb.String()), 0644); err != nil {
+		fmt.Printf("ERROR: Failed to write snapshot file (path: %s) Err: %s\n", *path, err.Error())
+		return err
+	}
+	return nil
+}
+
+// Start from the latest snapshot before the first day instead of initial balance
+// Snapshots from the first day on are closed periods recalculated by this run
+func ApplySnapshot(config *Config, snapshots ListSnapshot, days *ListSingleEntry) {
+	first := (*days)[0].date
+
+	var start *Snapshot
+	overlap := make(ListSnapshot, 0)
+	for _, s := range snapshots {
+		if s.date.Before(first) {
+			start = s
+		} else {
+			overlap = append(overlap, s)
+		}
+	}
+
+	if len(overlap) > 0 {
+		fmt.Printf("WARNING: Import overlaps %v already closed period(s) ending %s - %s. Their snapshots are replaced with the balances of this run.\n",
+			len(overlap), overlap[0].date.Format(*config.DateParseLayout), overlap[len(overlap)-1].date.Format(*config.DateParseLayout))
+	}
+
+	if start == nil {
+		return
+	}
+
+	// Workdays between the snapshot and import are not counted
+	for d := start.date.AddDate(0, 0, 1); d.Before(first); d = d.AddDate(0, 0, 1) {
+		if config.ExcludedWeekdays == nil || !ValueInArray(AsPtr(d.Weekday()), config.ExcludedWeekdays) {
+			fmt.Printf("WARNING: Import starts %s, days after the latest snapshot (%s) are missing from the balance.\n",
+				first.Format(*config.DateParseLayout), start.date.Format(*config.DateParseLayout))
+			break
+		}
+	}
+
+	if config.InitialBalance != nil {
+		fmt.Println("NOTE: Initial balance from config ignored, using snapshot instead.")
+	}
+	fmt.Printf("Starting from snapshot of %s with balance: %s\n", start.date.Format(*config.DateParseLayout), *PlusSignIfNecessary(start.balance))
+	fmt.Println()
+	config.InitialBalance = AsPtr(start.balance)
+}
+
+// Record closing balance of every period the report covers until its end
+// Periods ending today or later are still open
+// Returns the updated snapshots and whether anything changed
+func RecordSnapshots(config *Config, snapshots ListSnapshot, report *Report, now time.Time) (ListSnapshot, bool) {
+	today := DateKey(now)
+	changed := false
+
+	// Rest of the period are excluded weekdays only
+	reachesEnd := func(date time.Time, end time.Time) bool {
+		for d := date.AddDate(0, 0, 1); !d.After(end); d = d.AddDate(0, 0, 1) {
+			if config.ExcludedWeekdays == nil || !ValueInArray(AsPtr(d.Weekday()), config.ExcludedWeekdays) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for i, d := range report.days {
+		end := PeriodEnd(config.SnapshotPeriod, d.entry.date)
+		if !end.Before(today) {
+			break
+		}
+		// Period closes on its last day in report
+		if i+1 < len(report.days) && !report.days[i+1].entry.date.After(end) {
+			continue
+		}
+		if i+1 >= len(report.days) && !reachesEnd(d.entry.date, end) {
+			break
+		}
+
+		idx := slices.IndexFunc(snapshots, func(s *Snapshot) bool { return s.date.Equal(end) })
+		if idx < 0 {
+			snapshots = append(snapshots, &Snapshot{date: end, balance: d.balance})
+			fmt.Printf("Recorded snapshot of %s with balance: %s\n", end.Format(*config.DateParseLayout), *PlusSignIfNecessary(d.balance))
+			changed = true
+			continue
+		}
+		if snapshots[idx].balance != d.balance {
+			fmt.Printf("NOTE: Snapshot of %s changed: %s => %s\n", end.Format(*config.DateParseLayout),
+				*PlusSignIfNecessary(snapshots[idx].balance), *PlusSignIfNecessary(d.balance))
+			snapshots[idx].balance = d.balance
+			changed = true
+		}
+	}
+
+	slices.SortFunc(snapshots, func(a, b *Snapshot) int { return a.date.Compare(b.date) })
+
+	return snapshots, changed
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPeriodEnd(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse("02.01.2006", s)
+		return d
+	}
+
+	tests := []struct {
+		period SnapshotPeriod
+		date   string
+		want   string
+	}{
+		{period: YEAR_PERIOD, date: "15.06.2023", want: "31.12.2023"},
+		{period: YEAR_PERIOD, date: "31.12.2023", want: "31.12.2023"},
+		{period: MONTH_PERIOD, date: "01.02.2024", want: "29.02.2024"},
+		{period: MONTH_PERIOD, date: "15.12.2023", want: "31.12.2023"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			if got := PeriodEnd(tt.period, date(tt.date)); !got.Equal(date(tt.want)) {
+				t.Errorf("got %s, want %s", got.Format("02.01.2006"), tt.want)
+			}
+		})
+	}
+}
+
+func TestReadSnapshots(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  *string
+		balances []Hours
+		wantErr  bool
+	}{
+		{name: "missing file", balances: []Hours{}},
+		{
+			name:     "sorted by date, comments skipped",
+			content:  AsPtr("# header\n2023-12-31 +12.50\n\n2023-11-30 -1,25\n"),
+			balances: []Hours{-125, 1250},
+		},
+		{name: "missing balance", content: AsPtr("2023-12-31\n"), wantErr: true},
+		{name: "invalid date", content: AsPtr("31.12.2023 +1.00\n"), wantErr: true},
+		{name: "invalid balance", content: AsPtr("2023-12-31 abc\n"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "snapshots.txt")
+			if tt.content != nil {
+				if err := os.WriteFile(path, []byte(*tt.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			snapshots, err := ReadSnapshots(&path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(snapshots) != len(tt.balances) {
+				t.Fatalf("got %v snapshots, want %v", len(snapshots), len(tt.balances))
+			}
+			for i, s := range snapshots {
+				if s.balance != tt.balances[i] {
+					t.Errorf("snapshot %v: got balance %v, want %v", i, s.balance, tt.balances[i])
+				}
+			}
+		})
+	}
+}
+
+// Written snapshots read back the same
+func TestWriteSnapshots(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshots.txt")
+	snapshots := ListSnapshot{
+		{date: time.Date(2022, time.December, 31, 0, 0, 0, 0, time.UTC), balance: -350},
+		{date: time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC), balance: 0},
+	}
+
+	if err := WriteSnapshots(&path, snapshots); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	got, err := ReadSnapshots(&path)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if len(got) != len(snapshots) {
+		t.Fatalf("got %v snapshots, want %v", len(got), len(snapshots))
+	}
+	for i, s := range got {
+		if !s.date.Equal(snapshots[i].date) || s.balance != snapshots[i].balance {
+			t.Errorf("snapshot %v: got %s %v, want %s %v", i, s.date.Format(ISO_DATE_LAYOUT), s.balance,
+				snapshots[i].date.Format(ISO_DATE_LAYOUT), snapshots[i].balance)
+		}
+	}
+}
+
+func TestApplySnapshot(t *testing.T) {
+	snapshots := ListSnapshot{
+		{date: time.Date(2022, time.December, 31, 0, 0, 0, 0, time.UTC), balance: 200},
+		{date: time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC), balance: 500},
+	}
+
+	tests := []struct {
+		name    string
+		first   string
+		initial *Hours
+		want    *Hours
+	}{
+		{name: "no earlier snapshot", first: "01.06.2022", initial: AsPtr[Hours](150), want: AsPtr[Hours](150)},
+		{name: "latest earlier snapshot", first: "02.01.2024", want: AsPtr[Hours](500)},
+		{name: "overrides initial balance", first: "02.01.2023", initial: AsPtr[Hours](150), want: AsPtr[Hours](200)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				DateParseLayout:  AsPtr("02.01.2006"),
+				ExcludedWeekdays: &ListWeekday{AsPtr(time.Saturday), AsPtr(time.Sunday)},
+				InitialBalance:   tt.initial,
+			}
+
+			ApplySnapshot(config, snapshots, reportEntries(tt.first, 725))
+			if (config.InitialBalance == nil) != (tt.want == nil) || (tt.want != nil && *config.InitialBalance != *tt.want) {
+				t.Errorf("got initial balance %v, want %v", config.InitialBalance, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordSnapshots(t *testing.T) {
+	report := func(days ...any) *Report {
+		r := &Report{}
+		var balance Hours
+		for _, e := range *reportEntries(days...) {
+			balance += e.duration - 725
+			r.days = append(r.days, &ReportDay{entry: e, balance: balance})
+		}
+		return r
+	}
+	now := time.Date(2024, time.February, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		period    SnapshotPeriod
+		snapshots ListSnapshot
+		report    *Report
+		balances  []Hours
+		changed   bool
+	}{
+		{
+			name:     "closed months until last day",
+			period:   MONTH_PERIOD,
+			report:   report("30.11.2023", 825, "29.12.2023", 725, "31.01.2024", 825, "14.02.2024", 725),
+			balances: []Hours{100, 100, 200},
+			changed:  true,
+		},
+		{
+			name:     "last day followed by excluded weekdays",
+			period:   YEAR_PERIOD,
+			report:   report("29.12.2023", 925),
+			balances: []Hours{200},
+			changed:  true,
+		},
+		{
+			name:     "period not reached its end",
+			period:   MONTH_PERIOD,
+			report:   report("28.12.2023", 925),
+			balances: []Hours{},
+		},
+		{
+			name:      "changed balance replaced",
+			period:    YEAR_PERIOD,
+			snapshots: ListSnapshot{{date: time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC), balance: 50}},
+			report:    report("29.12.2023", 925),
+			balances:  []Hours{200},
+			changed:   true,
+		},
+		{
+			name:      "unchanged balance",
+			period:    YEAR_PERIOD,
+			snapshots: ListSnapshot{{date: time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC), balance: 200}},
+			report:    report("29.12.2023", 925),
+			balances:  []Hours{200},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				DateParseLayout:  AsPtr("02.01.2006"),
+				ExcludedWeekdays: &ListWeekday{AsPtr(time.Saturday), AsPtr(time.Sunday)},
+				SnapshotPeriod:   tt.period,
+			}
+
+			snapshots, changed := RecordSnapshots(config, tt.snapshots, tt.report, now)
+			if changed != tt.changed {
+				t.Errorf("got changed %v, want %v", changed, tt.changed)
+			}
+			if len(snapshots) != len(tt.balances) {
+				t.Fatalf("got %v snapshots, want %v", len(snapshots), len(tt.balances))
+			}
+			for i, s := range snapshots {
+				if s.balance != tt.balances[i] {
+					t.Errorf("snapshot %v: got balance %v, want %v", i, s.balance, tt.balances[i])
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"io"
 	"regexp"
 	"time"
 )
@@ -10,12 +11,15 @@ import (
 
 // Order from most primitive to most advanced
 
-type ImportFileType uint8
+type ImportKind uint8
+type DayType uint8
 type OperationMode uint8
+type SnapshotPeriod uint8
 type Line uint32  // 0-2^32 lines?
 type Column uint8 // 0-255 columns?
 type Year uint16  // 1-9999
 type Month uint8  // 1-12
+type Hours int64  // Fixed-point, hundredths of an hour
 
 type ListWeekday []*time.Weekday
 type ListString []*string
@@ -23,60 +27,341 @@ type ListString []*string
 type ListColumn []*Column
 type ListWeekEntry []*WeekEntry
 type ListSingleEntry []*SingleEntry
+type ListTimeEntry []*TimeEntry
+type ListContract []*Contract
+type ListCheckRow []*CheckRow
+type ListReportDay []*ReportDay
+type ListReportWeek []*ReportWeek
+type ListExportFile []*ExportFile
+type ListJournalSession []*JournalSession
+type ListImportFile []*ImportFile
+type ListSnapshot []*Snapshot
 
 type FieldMap map[int]bool // int required for indexing
+type ColumnIndexMap map[Column]int
+type ColumnAliasMap map[Column]*ListString
+type ColumnNameMap map[Column]*string
 type StringPtrMap map[string]*string
 type WeekdayMap map[string]*time.Weekday
+type SectionMap map[string]*ListString
 
-type ImportFileTypeMap map[string]ImportFileType
+type ImporterMap map[string]Importer
+type ModeHandlerMap map[OperationMode]*ModeHandler
+type JournalCommandMap map[string]FuncJournalCommand
 type OperationModeMap map[string]OperationMode
+type ExporterMap map[string]Exporter
+type DayTypeMap map[string]DayType
+type DayTypeDateMap map[time.Time]DayType
+type ConfigColumnMap map[string]Column
 
 type WeekdayRevMap map[time.Weekday]*string
 type OperationModeRevMap map[OperationMode]*string
+type SnapshotPeriodMap map[string]SnapshotPeriod
+type SnapshotPeriodRevMap map[SnapshotPeriod]*string
+type ImportKindRevMap map[ImportKind]*string
+type DayTypeRevMap map[DayType]*string
 
+type HolidayRuleFunc func(year int) []*Holiday
+type HolidayMap map[time.Time]*Holiday
+type HolidayRulesMap map[string]HolidayRuleFunc
+
+type FuncModeRun func(config *Config, global *Common, data *ImportData, export bool) error
 type FuncPrintf func(format string, a ...any) (n int, err error)
 type FuncPrintln func(a ...any) (n int, err error)
+type FuncJournalCommand func(path *string, sessions ListJournalSession, now time.Time) error
 
 type Config struct {
-	IfType           ImportFileType
+	FileType         *string
+	Importer         Importer
 	Mode             OperationMode
+	ExportFormats    *ListString
+	ImportFiles      *ListImportFile
 	ImportFilePath   *string
 	ImportFileName   *string
+	ExportDir        *string
 	ExportFilePath   *string
 	ExportFileName   *string
-	CsvDelimiter     *string
+	JournalPath      *string
+	SnapshotPath     *string
+	SnapshotPeriod   SnapshotPeriod
+	ReportFrom       *time.Time
+	ReportTo         *time.Time
+	CsvDelimiter     rune
 	DateParseLayout  *string
 	ExcludedWeekdays *ListWeekday
 	ExcludedTasks    *ListString
-	InitialBalance   *float64
-	DailyHours       float64
+	ClockifyColumns  *ColumnAliasMap
+	Holidays         *HolidayCalendar
+	Contracts        *ListContract
+	TaskDayTypes     DayTypeMap
+	Absences         DayTypeDateMap
+	InitialBalance   *Hours
+	DailyHours       Hours
+	CsvWeekly        bool
+}
+
+// Reads an import file of a single file type into entries
+// Registered by name, selected with file type in config
+type Importer interface {
+	// Kind of entries produced
+	Kind() ImportKind
+	// Parse the entries from opened import file
+	Import(config *Config, reader io.Reader) (*ImportData, error)
+}
+
+// Entries parsed from import files
+// Weeks for custom files, time records for exports, depending on importer kind
+// Days are combined from the time records of all import files
+type ImportData struct {
+	weeks   *ListWeekEntry
+	entries *ListTimeEntry
+	days    *ListSingleEntry
+}
+
+// Single file matched by import path, with its own file type
+type ImportFile struct {
+	path     *string
+	name     *string
+	fileType *string
+	importer Importer
+}
+
+// Dates covered by the time records of an import file
+type ImportRange struct {
+	file  *ImportFile
+	first time.Time
+	last  time.Time
+}
+
+// Writes the results of a run in a single output format
+// Registered by name, selected with export format in config
+type Exporter interface {
+	// Files to write for the data, nil if the data is not supported
+	Files(config *Config, global *Common, data *ExportData) ListExportFile
+}
+
+// Results of a run to export, only one of them set
+// Report from daily entries, weeks from custom files or check results
+type ExportData struct {
+	report *Report
+	weeks  *ListWeekEntry
+	check  *CheckResult
+}
+
+// Single output file of an exporter
+// Named Report_<date>[_<suffix>].<ext> in export dir
+type ExportFile struct {
+	suffix *string
+	ext    string
+	write  func(w io.Writer) error
+}
+
+// Operation mode and the import kinds it can handle
+type ModeHandler struct {
+	kinds []ImportKind
+	run   FuncModeRun
+}
+
+// Command line arguments
+// Overrides hold config keys whose value was given as flag
+type Args struct {
+	Command    *string
+	ConfigPath *string
+	Overrides  StringPtrMap
+	Once       bool
+	Export     bool
 }
 
 type Common struct {
-	weeklyHours float64
+	weeklyHours Hours
+}
+
+// Location of a value in the import file
+// Start and end are byte offsets on the line
+type FieldPos struct {
+	line  Line
+	start int
+	end   int
 }
 
 type WeekEntry struct {
-	trange  *string
-	comment *string
-	year    Year
-	month   Month
-	worked  float64
-	diff    float64
-	balance float64
+	trange     *string
+	comment    *string
+	start      time.Time
+	end        time.Time
+	diffPos    *FieldPos
+	balancePos *FieldPos
+	dayTypes   DayTypeDateMap
+	year       Year
+	month      Month
+	worked     Hours
+	diff       Hours
+	balance    Hours
+}
+
+// Check outcome of a single custom file entry
+// Expected balance is the previous reported balance plus expected diff
+// Recalculated balance accumulates expected diffs from the initial balance
+type CheckRow struct {
+	entry               *WeekEntry
+	errors              ListString
+	index               int
+	required            Hours
+	expectedDiff        Hours
+	expectedBalance     Hours
+	recalculatedBalance Hours
+	continuityError     bool
+}
+
+// Check outcome of the whole custom file
+// Divergence is the first entry where reported balance differs from expected
+type CheckResult struct {
+	rows       ListCheckRow
+	divergence *CheckRow
+	errorCount int
+	balance    Hours
+}
+
+// Daily work hour requirement for a date range
+// Open ended if either date not set, applies to all weekdays if weekday not set
+type Contract struct {
+	from    *time.Time
+	to      *time.Time
+	weekday *time.Weekday
+	hours   Hours
+}
+
+// Single non-working day
+// Hours set if the day has reduced requirement instead of none
+type Holiday struct {
+	date  time.Time
+	name  *string
+	hours *Hours
+}
+
+// Built-in rules generate holidays per year when first needed
+// Holidays from user files take precedence
+type HolidayCalendar struct {
+	rules   HolidayRuleFunc
+	years   map[int]bool
+	builtin HolidayMap
+	custom  HolidayMap
 }
 
 type SingleEntry struct {
 	date     time.Time
-	duration float64
+	duration Hours
+	dtype    DayType
+}
+
+// Single time record of an export before combining into days
+// Names are lowercase, tasks are matched against excluded tasks
+// Labels (e.g. task, project, tags) are matched against absence day type tasks
+type TimeEntry struct {
+	date     time.Time
+	duration Hours
+	line     Line
+	tasks    ListString
+	labels   ListString
+}
+
+// Single content line of ics file after unfolding
+// Name and parameter names are uppercase
+type IcsProperty struct {
+	name   string
+	params map[string]string
+	value  string
+	line   Line
+}
+
+// Calendar event of an ics file
+// Recurrence id set if the event replaces a single occurrence of a recurring event
+type IcsEvent struct {
+	uid          *string
+	start        *time.Time
+	end          *time.Time
+	duration     *time.Duration
+	recurrenceId *time.Time
+	rule         *IcsRule
+	exdates      []time.Time
+	tasks        ListString
+	labels       ListString
+	allDay       bool
+	cancelled    bool
+	line         Line
+}
+
+// Recurrence rule of an event (RRULE)
+// Count zero if not limited
+type IcsRule struct {
+	freq     string
+	interval int
+	count    int
+	until    *time.Time
+	byDay    []time.Weekday
+}
+
+// Work session replayed from punch clock journal
+// End is nil while still working, break start set while on break
+type JournalSession struct {
+	start      time.Time
+	end        *time.Time
+	breaks     time.Duration
+	breakStart *time.Time
+	line       Line
+}
+
+// Closing balance at the last day of a report period
+type Snapshot struct {
+	date    time.Time
+	balance Hours
+}
+
+// Single day of the report with calculated values
+// Balance is the running balance after the day
+type ReportDay struct {
+	entry    *SingleEntry
+	holiday  *Holiday
+	index    int
+	required Hours
+	diff     Hours
+	balance  Hours
+}
+
+// Days of the same ISO week
+// Index is the running week number from the start of the report
+type ReportWeek struct {
+	days     ListReportDay
+	start    time.Time
+	end      time.Time
+	index    int
+	year     int
+	week     int
+	worked   Hours
+	required Hours
+	diff     Hours
+	balance  Hours
+}
+
+// Calculated report shared by all output formats
+// Initial is the balance before the first day
+type Report struct {
+	days    ListReportDay
+	weeks   ListReportWeek
+	initial Hours
+	balance Hours
 }
 
 var (
 	DECIMAL_REGEX              = regexp.MustCompile(`[0-9]|[1-9][0-9](\,|\.)[0-9]|[1-9][0-9][0-9]?`)
 	SIGNED_DECIMAL_REGEX       = regexp.MustCompile(`[+\-]` + DECIMAL_REGEX.String())
 	PAREN_SIGNED_DECIMAL_REGEX = regexp.MustCompile(`\(` + SIGNED_DECIMAL_REGEX.String() + `\)`)
-	DATERANGE_REGEX            = regexp.MustCompile(`([1-9]|[1-2][0-9]|3[0-1])\.([1-9]|1[012]\.)?\-([1-9]|[1-2][0-9]|3[0-1])\.([1-9]|1[012])\.`)
+	DATERANGE_REGEX            = regexp.MustCompile(`(0?[1-9]|[1-2][0-9]|3[0-1])\.(?:(0?[1-9]|1[012])\.)?\-(0?[1-9]|[1-2][0-9]|3[0-1])\.(0?[1-9]|1[012])\.`)
 	COMMENT_REGEX              = regexp.MustCompile(`[A-Z]`)
+	WORD_REGEX                 = regexp.MustCompile(`\S+`)
+	DAYTYPE_COMMENT_REGEX      = regexp.MustCompile(`(VACATION|SICK|HOLIDAY|FLEX|UNPAID)((?:\s+(?:0?[1-9]|[1-2][0-9]|3[0-1])\.(?:0?[1-9]|1[012])\.)+)`)
+	ICS_DURATION_REGEX         = regexp.MustCompile(`^([+\-])?P(?:([0-9]+)W)?(?:([0-9]+)D)?(?:T(?:([0-9]+)H)?(?:([0-9]+)M)?(?:([0-9]+)S)?)?$`)
+	DAYMONTH_REGEX             = regexp.MustCompile(`(0?[1-9]|[1-2][0-9]|3[0-1])\.(0?[1-9]|1[012])\.`)
 	YEARMONTH_REGEX            = regexp.MustCompile(`[1-9][0-9]{3}\-(0?[1-9]|1[012])`)
 	// DATE_REGEX                 = regexp.MustCompile(`(0?[1-9]|[1-2][0-9]|3[0-1])\.(0?[1-9]|1[012])\.[1-9][0-9]{3}`)
 )
@@ -92,6 +377,29 @@ const (
 	CNF_EXCLUDED_WEEKDAYS_STR string = "excluded_weekdays"
 	CNF_INITIAL_BALANCE_STR   string = "initial_balance"
 	CNF_EXCLUDED_TASKS_STR    string = "excluded_clockify_tasks"
+	CNF_CLOCKIFY_TASK_STR     string = "clockify_task_column"
+	CNF_CLOCKIFY_DATE_STR     string = "clockify_date_column"
+	CNF_CLOCKIFY_DURATION_STR string = "clockify_duration_column"
+	CNF_HOLIDAY_CALENDAR_STR  string = "holiday_calendar"
+	CNF_HOLIDAY_FILES_STR     string = "holiday_files"
+	CNF_CLOCKIFY_PROJECT_STR  string = "clockify_project_column"
+	CNF_VACATION_TASKS_STR    string = "vacation_tasks"
+	CNF_SICK_TASKS_STR        string = "sick_tasks"
+	CNF_FLEX_TASKS_STR        string = "flex_tasks"
+	CNF_UNPAID_TASKS_STR      string = "unpaid_tasks"
+	CNF_ABSENCES_FILE_STR     string = "absences_file"
+	CNF_EXPORT_FORMAT_STR     string = "export_format"
+	CNF_CSV_WEEKLY_STR        string = "export_csv_weekly"
+	CNF_JOURNAL_PATH_STR      string = "journal_path"
+	CNF_SNAPSHOT_PATH_STR     string = "snapshot_path"
+	CNF_SNAPSHOT_PERIOD_STR   string = "snapshot_period"
+	CNF_REPORT_FROM_STR       string = "report_from"
+	CNF_REPORT_TO_STR         string = "report_to"
+)
+
+// Config sections, lines parsed separately
+const (
+	CNF_CONTRACTS_SECTION_STR string = "contracts"
 )
 
 func EmptyConfigurationMapping() StringPtrMap {
@@ -106,22 +414,135 @@ func EmptyConfigurationMapping() StringPtrMap {
 		CNF_EXCLUDED_WEEKDAYS_STR: nil,
 		CNF_INITIAL_BALANCE_STR:   nil,
 		CNF_EXCLUDED_TASKS_STR:    nil,
+		CNF_CLOCKIFY_TASK_STR:     nil,
+		CNF_CLOCKIFY_DATE_STR:     nil,
+		CNF_CLOCKIFY_DURATION_STR: nil,
+		CNF_HOLIDAY_CALENDAR_STR:  nil,
+		CNF_HOLIDAY_FILES_STR:     nil,
+		CNF_CLOCKIFY_PROJECT_STR:  nil,
+		CNF_VACATION_TASKS_STR:    nil,
+		CNF_SICK_TASKS_STR:        nil,
+		CNF_FLEX_TASKS_STR:        nil,
+		CNF_UNPAID_TASKS_STR:      nil,
+		CNF_ABSENCES_FILE_STR:     nil,
+		CNF_EXPORT_FORMAT_STR:     nil,
+		CNF_CSV_WEEKLY_STR:        nil,
+		CNF_JOURNAL_PATH_STR:      nil,
+		CNF_SNAPSHOT_PATH_STR:     nil,
+		CNF_SNAPSHOT_PERIOD_STR:   nil,
+		CNF_REPORT_FROM_STR:       nil,
+		CNF_REPORT_TO_STR:         nil,
 	}
 }
 
-// Define column constants
+// Config keys with values kept in original case
+// Paths are case sensitive on most file systems, date layout and dates on all
+// CONSTANT READONLY
+var ConfigCaseSensitiveKeys = ListString{
+	AsPtr(CNF_IMPORT_PATH_STR),
+	AsPtr(CNF_EXPORT_PATH_STR),
+	AsPtr(CNF_DATE_PARSE_STR),
+	AsPtr(CNF_HOLIDAY_FILES_STR),
+	AsPtr(CNF_ABSENCES_FILE_STR),
+	AsPtr(CNF_JOURNAL_PATH_STR),
+	AsPtr(CNF_SNAPSHOT_PATH_STR),
+	AsPtr(CNF_REPORT_FROM_STR),
+	AsPtr(CNF_REPORT_TO_STR),
+}
+
+// Defaults for csv delimiter and date layout when not in config
+const (
+	DEFAULT_CSV_DELIMITER = ","
+	DEFAULT_DATE_LAYOUT   = "02.01.2006"
+)
+
+// Command line flag names
+const (
+	ARG_CONFIG_STR      string = "config"
+	ARG_IMPORT_STR      string = "import"
+	ARG_MODE_STR        string = "mode"
+	ARG_FILE_TYPE_STR   string = "file-type"
+	ARG_EXPORT_DIR_STR  string = "export-dir"
+	ARG_DAILY_HOURS_STR string = "daily-hours"
+	ARG_ONCE_STR        string = "once"
+	ARG_EXPORT_STR      string = "export"
+	ARG_FORMAT_STR      string = "format"
+	ARG_CSV_WEEKLY_STR  string = "csv-weekly"
+	ARG_JOURNAL_STR     string = "journal"
+	ARG_FROM_STR        string = "from"
+	ARG_TO_STR          string = "to"
+)
+
+// Flags which override a config file key
+// CONSTANT READONLY
+var ArgConfigMapping = StringPtrMap{
+	ARG_IMPORT_STR:      AsPtr(CNF_IMPORT_PATH_STR),
+	ARG_MODE_STR:        AsPtr(CNF_MODE_STR),
+	ARG_FILE_TYPE_STR:   AsPtr(CNF_FILE_TYPE_STR),
+	ARG_EXPORT_DIR_STR:  AsPtr(CNF_EXPORT_PATH_STR),
+	ARG_DAILY_HOURS_STR: AsPtr(CNF_DAILY_HOURS_STR),
+	ARG_FORMAT_STR:      AsPtr(CNF_EXPORT_FORMAT_STR),
+	ARG_CSV_WEEKLY_STR:  AsPtr(CNF_CSV_WEEKLY_STR),
+	ARG_JOURNAL_STR:     AsPtr(CNF_JOURNAL_PATH_STR),
+	ARG_FROM_STR:        AsPtr(CNF_REPORT_FROM_STR),
+	ARG_TO_STR:          AsPtr(CNF_REPORT_TO_STR),
+}
+
+// Punch clock commands given as first argument
+const (
+	CMD_START_STR  string = "start"
+	CMD_STOP_STR   string = "stop"
+	CMD_BREAK_STR  string = "break"
+	CMD_STATUS_STR string = "status"
+)
+
+// Punch clock journal events, written after the timestamp on each line
+const (
+	JOURNAL_START  string = "start"
+	JOURNAL_STOP   string = "stop"
+	JOURNAL_BREAK  string = "break"
+	JOURNAL_RESUME string = "resume"
+)
+
+// Default journal file, next to config file
+const JOURNAL_FILE = "journal.txt"
+
+// Machine readable date and time formats of exports
+const (
+	ISO_DATE_LAYOUT   = "2006-01-02"
+	ISO_TIME_LAYOUT   = "15:04:05"
+	SHORT_TIME_LAYOUT = "15:04"
+)
+
+// Date and date-time value formats of ics files
+const (
+	ICS_DATE_LAYOUT     = "20060102"
+	ICS_DATETIME_LAYOUT = "20060102T150405"
+)
+
+// Safety limit for expanding recurring events
+const ICS_MAX_PERIODS = 100000
+
+// Fixed-point scale of Hours
+const HOURS_SCALE Hours = 100
+
+// Process exit codes in non-interactive mode
 const (
-	COL_CLOCKIFY_TASK     Column = 3
-	COL_CLOCKIFY_DATE     Column = 9
-	COL_CLOCKIFY_DURATION Column = 14
+	EXIT_OK      int = 0
+	EXIT_FAILURE int = 1
+	EXIT_CONFIG  int = 2
 )
 
-// Highest value of columns here
-// To determine min columns needed from input file
-const COL_CLOCKIFY_MAXCOL = max(
-	COL_CLOCKIFY_TASK,
-	COL_CLOCKIFY_DATE,
-	COL_CLOCKIFY_DURATION,
+// Highest position of a located column, positions are stored as Column
+const COL_MAX_POSITION = int(^Column(0))
+
+// Define logical column constants
+// Actual column positions are resolved from the header row
+const (
+	COL_CLOCKIFY_TASK Column = iota
+	COL_CLOCKIFY_DATE
+	COL_CLOCKIFY_DURATION
+	COL_CLOCKIFY_PROJECT
 )
 
 func NewClockifyExportColumns() *ListColumn {
@@ -129,45 +550,274 @@ func NewClockifyExportColumns() *ListColumn {
 		AsPtr(COL_CLOCKIFY_TASK),
 		AsPtr(COL_CLOCKIFY_DATE),
 		AsPtr(COL_CLOCKIFY_DURATION),
+		AsPtr(COL_CLOCKIFY_PROJECT),
+	}
+}
+
+// Columns not required to exist in import file
+func NewClockifyOptionalColumns() *ListColumn {
+	return &ListColumn{
+		AsPtr(COL_CLOCKIFY_PROJECT),
 	}
 }
 
-// Input data source
-// Determines way of handling input
-// Custom: handmade balance.txt
-// Export: e.g. from Clockify App excel/csv export
+// Readable column names for messages
+// CONSTANT READONLY
+var ClockifyColumnNameMapping = ColumnNameMap{
+	COL_CLOCKIFY_TASK:     AsPtr("Task"),
+	COL_CLOCKIFY_DATE:     AsPtr("Start Date"),
+	COL_CLOCKIFY_DURATION: AsPtr("Duration (decimal)"),
+	COL_CLOCKIFY_PROJECT:  AsPtr("Project"),
+}
+
+// Logical columns of Toggl Track detailed export
 const (
-	CUSTOM_FILE ImportFileType = iota
-	CUSTOM_SHORT_FILE
-	CLOCKIFY_FILE
+	COL_TOGGL_DATE Column = iota
+	COL_TOGGL_TIME
+	COL_TOGGL_DURATION
+	COL_TOGGL_PROJECT
+	COL_TOGGL_TASK
+	COL_TOGGL_DESCRIPTION
+	COL_TOGGL_TAGS
 )
 
-// Possible config values for inputfiletype
-// ENSURE CORRECT ORDERING FROM ABOVE
+// Columns not required to exist in import file
+func NewTogglOptionalColumns() *ListColumn {
+	return &ListColumn{
+		AsPtr(COL_TOGGL_TIME),
+		AsPtr(COL_TOGGL_PROJECT),
+		AsPtr(COL_TOGGL_TASK),
+		AsPtr(COL_TOGGL_DESCRIPTION),
+		AsPtr(COL_TOGGL_TAGS),
+	}
+}
+
+// Readable column names for messages
 // CONSTANT READONLY
-var InputFileTypeMapping = ImportFileTypeMap{
-	"custom":          CUSTOM_FILE,
-	"customshort":     CUSTOM_SHORT_FILE,
-	"clockify_export": CLOCKIFY_FILE,
+var TogglColumnNameMapping = ColumnNameMap{
+	COL_TOGGL_DATE:        AsPtr("Start date"),
+	COL_TOGGL_TIME:        AsPtr("Start time"),
+	COL_TOGGL_DURATION:    AsPtr("Duration"),
+	COL_TOGGL_PROJECT:     AsPtr("Project"),
+	COL_TOGGL_TASK:        AsPtr("Task"),
+	COL_TOGGL_DESCRIPTION: AsPtr("Description"),
+	COL_TOGGL_TAGS:        AsPtr("Tags"),
 }
 
+// Default header names (lowercase) for each column
+func NewTogglColumnAliases() *ColumnAliasMap {
+	return &ColumnAliasMap{
+		COL_TOGGL_DATE:        &ListString{AsPtr("start date")},
+		COL_TOGGL_TIME:        &ListString{AsPtr("start time")},
+		COL_TOGGL_DURATION:    &ListString{AsPtr("duration")},
+		COL_TOGGL_PROJECT:     &ListString{AsPtr("project")},
+		COL_TOGGL_TASK:        &ListString{AsPtr("task")},
+		COL_TOGGL_DESCRIPTION: &ListString{AsPtr("description")},
+		COL_TOGGL_TAGS:        &ListString{AsPtr("tags")},
+	}
+}
+
+// Logical columns of simple timesheet exports, e.g. Harvest and Kimai
+// One time record per row
+const (
+	COL_SHEET_DATE Column = iota
+	COL_SHEET_DURATION
+	COL_SHEET_PROJECT
+	COL_SHEET_TASK
+)
+
+// Columns not required to exist in import file
+func NewSheetOptionalColumns() *ListColumn {
+	return &ListColumn{
+		AsPtr(COL_SHEET_PROJECT),
+		AsPtr(COL_SHEET_TASK),
+	}
+}
+
+// Readable column names for messages
+// CONSTANT READONLY
+var HarvestColumnNameMapping = ColumnNameMap{
+	COL_SHEET_DATE:     AsPtr("Date"),
+	COL_SHEET_DURATION: AsPtr("Hours"),
+	COL_SHEET_PROJECT:  AsPtr("Project"),
+	COL_SHEET_TASK:     AsPtr("Task"),
+}
+
+// Default header names (lowercase) for each column
+func NewHarvestColumnAliases() *ColumnAliasMap {
+	return &ColumnAliasMap{
+		COL_SHEET_DATE:     &ListString{AsPtr("date"), AsPtr("spent date")},
+		COL_SHEET_DURATION: &ListString{AsPtr("hours")},
+		COL_SHEET_PROJECT:  &ListString{AsPtr("project")},
+		COL_SHEET_TASK:     &ListString{AsPtr("task")},
+	}
+}
+
+// Readable column names for messages
+// CONSTANT READONLY
+var KimaiColumnNameMapping = ColumnNameMap{
+	COL_SHEET_DATE:     AsPtr("Date"),
+	COL_SHEET_DURATION: AsPtr("Duration"),
+	COL_SHEET_PROJECT:  AsPtr("Project"),
+	COL_SHEET_TASK:     AsPtr("Activity"),
+}
+
+// Default header names (lowercase) for each column
+func NewKimaiColumnAliases() *ColumnAliasMap {
+	return &ColumnAliasMap{
+		COL_SHEET_DATE:     &ListString{AsPtr("date")},
+		COL_SHEET_DURATION: &ListString{AsPtr("duration")},
+		COL_SHEET_PROJECT:  &ListString{AsPtr("project")},
+		COL_SHEET_TASK:     &ListString{AsPtr("activity")},
+	}
+}
+
+// Config keys for additional column header names
+// CONSTANT READONLY
+var ConfigClockifyColumnMapping = ConfigColumnMap{
+	CNF_CLOCKIFY_TASK_STR:     COL_CLOCKIFY_TASK,
+	CNF_CLOCKIFY_DATE_STR:     COL_CLOCKIFY_DATE,
+	CNF_CLOCKIFY_DURATION_STR: COL_CLOCKIFY_DURATION,
+	CNF_CLOCKIFY_PROJECT_STR:  COL_CLOCKIFY_PROJECT,
+}
+
+// Default header names (lowercase) for each column
+// Extended with aliases from config
+func NewClockifyColumnAliases() *ColumnAliasMap {
+	return &ColumnAliasMap{
+		COL_CLOCKIFY_TASK:     &ListString{AsPtr("task")},
+		COL_CLOCKIFY_DATE:     &ListString{AsPtr("start date")},
+		COL_CLOCKIFY_DURATION: &ListString{AsPtr("duration (decimal)")},
+		COL_CLOCKIFY_PROJECT:  &ListString{AsPtr("project")},
+	}
+}
+
+// Kind of entries an importer produces
+// Week: handmade balance.txt with weekly sums
+// Day: e.g. from Clockify App excel/csv export
+const (
+	WEEK_IMPORT ImportKind = iota
+	DAY_IMPORT
+)
+
+// CONSTANT READONLY
+var ImportKindRevMapping = ImportKindRevMap{
+	WEEK_IMPORT: AsPtr("weekly"),
+	DAY_IMPORT:  AsPtr("daily"),
+}
+
+// Registered importers by file type name
+// Filled by init functions of the importers
+var ImporterRegistry = ImporterMap{}
+
 const (
 	CHECK_MODE OperationMode = iota
 	REPORT_MODE
+	FIX_MODE
+	GENERATE_MODE
 )
 
 // Possible config values for operationmode
 // ENSURE CORRECT ORDERING FROM ABOVE
 // CONSTANT READONLY
 var OperationModeMapping = OperationModeMap{
-	"check":  CHECK_MODE,
-	"report": REPORT_MODE,
+	"check":    CHECK_MODE,
+	"report":   REPORT_MODE,
+	"fix":      FIX_MODE,
+	"generate": GENERATE_MODE,
 }
 
 // CONSTANT READONLY
 var OperationModeRevMapping = OperationModeRevMap{
-	CHECK_MODE:  AsPtr("check"),
-	REPORT_MODE: AsPtr("report"),
+	CHECK_MODE:    AsPtr("check"),
+	REPORT_MODE:   AsPtr("report"),
+	FIX_MODE:      AsPtr("fix"),
+	GENERATE_MODE: AsPtr("generate"),
+}
+
+// Balance snapshot periods
+const (
+	YEAR_PERIOD SnapshotPeriod = iota
+	MONTH_PERIOD
+)
+
+// CONSTANT READONLY
+var SnapshotPeriodMapping = SnapshotPeriodMap{
+	"year":  YEAR_PERIOD,
+	"month": MONTH_PERIOD,
+}
+
+// CONSTANT READONLY
+var SnapshotPeriodRevMapping = SnapshotPeriodRevMap{
+	YEAR_PERIOD:  AsPtr("year"),
+	MONTH_PERIOD: AsPtr("month"),
+}
+
+// Registered exporters by export format name
+// Filled by init functions of the exporters
+var ExporterRegistry = ExporterMap{}
+
+// Default export format if not set in config
+const DEFAULT_EXPORT_FORMAT = "text"
+
+// Kind of a single day
+// Determines the required hours for the day
+const (
+	WORK_DAY DayType = iota
+	VACATION_DAY
+	SICK_DAY
+	HOLIDAY_DAY
+	FLEX_OFF_DAY
+	UNPAID_DAY
+)
+
+// Possible day type values in absences file
+// CONSTANT READONLY
+var DayTypeMapping = DayTypeMap{
+	"work":     WORK_DAY,
+	"vacation": VACATION_DAY,
+	"sick":     SICK_DAY,
+	"holiday":  HOLIDAY_DAY,
+	"flex":     FLEX_OFF_DAY,
+	"unpaid":   UNPAID_DAY,
+}
+
+// CONSTANT READONLY
+var DayTypeRevMapping = DayTypeRevMap{
+	WORK_DAY:     AsPtr("work"),
+	VACATION_DAY: AsPtr("vacation"),
+	SICK_DAY:     AsPtr("sick"),
+	HOLIDAY_DAY:  AsPtr("holiday"),
+	FLEX_OFF_DAY: AsPtr("flex"),
+	UNPAID_DAY:   AsPtr("unpaid"),
+}
+
+// Config keys listing task/project names for absence day types
+// CONSTANT READONLY
+var ConfigDayTypeTasksMapping = DayTypeMap{
+	CNF_VACATION_TASKS_STR: VACATION_DAY,
+	CNF_SICK_TASKS_STR:     SICK_DAY,
+	CNF_FLEX_TASKS_STR:     FLEX_OFF_DAY,
+	CNF_UNPAID_TASKS_STR:   UNPAID_DAY,
+}
+
+// Possible config values for built-in holiday calendars
+// CONSTANT READONLY
+var HolidayRulesMapping = HolidayRulesMap{
+	"none": nil,
+	"fi":   FinnishHolidays,
+}
+
+// Weekday values of BYDAY in recurrence rules
+// CONSTANT READONLY
+var IcsWeekdayMapping = WeekdayMap{
+	"mo": AsPtr(time.Monday),
+	"tu": AsPtr(time.Tuesday),
+	"we": AsPtr(time.Wednesday),
+	"th": AsPtr(time.Thursday),
+	"fr": AsPtr(time.Friday),
+	"sa": AsPtr(time.Saturday),
+	"su": AsPtr(time.Sunday),
 }
 
 // CONSTANT READONLY
@@ -3,34 +3,58 @@ package main
 import (
 	"errors"
 	"fmt"
+	"io"
 	"slices"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
-func ParseInputFileType(str *string) (c ImportFileType, err error) {
+func ParseImporter(str *string) (Importer, error) {
+	if str == nil {
+		return nil, errors.New("ERROR: input ptr was null")
+	}
+	c, ok := ImporterRegistry[*str]
+	if !ok {
+		return nil, errors.New("ERROR: failed to parse given input to value")
+	}
+	return c, nil
+}
+
+func ParseOperationMode(str *string) (c OperationMode, err error) {
 	if str == nil {
 		return 255, errors.New("ERROR: input ptr was null")
 	}
-	c, ok := InputFileTypeMapping[*str]
+	c, ok := OperationModeMapping[*str]
 	if !ok {
 		return 255, errors.New("ERROR: failed to parse given input to value")
 	}
 	return
 }
 
-func ParseOperationMode(str *string) (c OperationMode, err error) {
+func ParseSnapshotPeriod(str *string) (c SnapshotPeriod, err error) {
 	if str == nil {
 		return 255, errors.New("ERROR: input ptr was null")
 	}
-	c, ok := OperationModeMapping[*str]
+	c, ok := SnapshotPeriodMapping[*str]
 	if !ok {
 		return 255, errors.New("ERROR: failed to parse given input to value")
 	}
 	return
 }
 
+func ParseExporter(str *string) (Exporter, error) {
+	if str == nil {
+		return nil, errors.New("ERROR: input ptr was null")
+	}
+	c, ok := ExporterRegistry[*str]
+	if !ok {
+		return nil, errors.New("ERROR: failed to parse given input to value")
+	}
+	return c, nil
+}
+
 func ParseWeekday(str *string) (*time.Weekday, error) {
 	if str == nil {
 		return nil, errors.New("ERROR: input ptr was null")
@@ -80,6 +104,15 @@ func (fmap *FieldMap) FieldsOk() bool {
 	return true
 }
 
+// Minimum column count for a row to contain all resolved columns
+func (cmap *ColumnIndexMap) MinColumns() int {
+	maxIdx := -1
+	for _, v := range *cmap {
+		maxIdx = max(maxIdx, v)
+	}
+	return maxIdx + 1
+}
+
 func ValidateMonthYearRow(str *string) (match bool) {
 	if str == nil {
 		return false
@@ -111,20 +144,38 @@ func ParseMonthYearRow(str *string) (year Year, month Month, err error) {
 	return
 }
 
-// Cannot define member functions on non-local member string
-func StrFloatFiToUs(str *string) string {
-	// todo more exact: replace only the correct pos in string
-	return strings.Replace(*str, ",", ".", 1)
-}
-
 func StrRemoveParentheses(str *string) string {
 	return strings.TrimPrefix(strings.TrimSuffix(*str, ")"), "(")
 }
 
+func StrRemoveBrackets(str *string) string {
+	return strings.TrimPrefix(strings.TrimSuffix(*str, "]"), "[")
+}
+
 func StrRemoveFromBothEnds(str *string, trim *string) string {
 	return strings.TrimPrefix(strings.TrimSuffix(*str, *trim), *trim)
 }
 
+// Parse CSV delimiter from config value
+// Value can be quoted: "," or plain: ;
+func ParseCsvDelimiter(str *string) (rune, error) {
+	if str == nil {
+		return 0, errors.New("ERROR: input ptr was null")
+	}
+	val := StrRemoveFromBothEnds(str, AsPtr("\""))
+	if val == `\t` || val == "tab" {
+		return '\t', nil
+	}
+	runes := []rune(val)
+	if len(runes) != 1 {
+		return 0, errors.New("ERROR: delimiter has to be a single character")
+	}
+	if runes[0] == '"' || runes[0] == '\r' || runes[0] == '\n' || runes[0] == utf8.RuneError {
+		return 0, errors.New("ERROR: invalid delimiter character")
+	}
+	return runes[0], nil
+}
+
 func AsPtr[T any](v T, u ...any) *T {
 	return &v
 }
@@ -139,10 +190,88 @@ func StringsJoin(arr *ListString, sep *string) *string {
 
 // Assign plus signs in front of decimal string representation
 // If value zero or above
-func PlusSignIfNecessary(val float64) *string {
+func PlusSignIfNecessary(val Hours) *string {
 	var s *string = AsPtr("")
 	if val >= 0 {
 		*s = "+"
 	}
-	return AsPtr(fmt.Sprintf("%s%.2f", *s, val))
+	return AsPtr(fmt.Sprintf("%s%s", *s, val))
+}
+
+// Date part only, used as a key for day lookups
+func DateKey(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Parse week date range (e.g. 30.10.-5.11. or 16.-22.10.) into dates
+// Year and month come from the month header the entry is listed under
+func ParseWeekRange(str *string, year Year, month Month) (start time.Time, end time.Time, err error) {
+	if year == 0 {
+		return start, end, errors.New("year-month unknown, missing month header before entry")
+	}
+	m := DATERANGE_REGEX.FindStringSubmatch(*str)
+	if m == nil {
+		return start, end, errors.New("invalid date range format")
+	}
+	startDay, _ := strconv.Atoi(m[1])
+	endDay, _ := strconv.Atoi(m[3])
+	endMonth, _ := strconv.Atoi(m[4])
+	startMonth := endMonth
+	if m[2] != "" {
+		startMonth, _ = strconv.Atoi(m[2])
+	}
+
+	startYear := (int)(year)
+	// Week crossing the year listed under January, e.g. 30.12.-5.1.
+	if startMonth > endMonth && (Month)(endMonth) == month {
+		startYear--
+	}
+	endYear := startYear
+	if startMonth > endMonth {
+		endYear++
+	}
+
+	start = time.Date(startYear, time.Month(startMonth), startDay, 0, 0, 0, 0, time.UTC)
+	end = time.Date(endYear, time.Month(endMonth), endDay, 0, 0, 0, 0, time.UTC)
+
+	// Catch overflowing dates like 31.11.
+	if start.Day() != startDay || end.Day() != endDay {
+		return start, end, errors.New("invalid date in date range")
+	}
+	if end.Before(start) {
+		return start, end, errors.New("date range end before start")
+	}
+	return start, end, nil
+}
+
+// Print functions writing into given writer
+func WriterPrintf(w io.Writer) FuncPrintf {
+	return func(format string, a ...any) (n int, err error) {
+		return fmt.Fprintf(w, format, a...)
+	}
+}
+
+func WriterPrintln(w io.Writer) FuncPrintln {
+	return func(a ...any) (n int, err error) {
+		return fmt.Fprintln(w, a...)
+	}
+}
+
+// Name of a dated report file in export dir, e.g. Report_2023-10-31_manual.txt
+func ReportFileName(suffix *string, ext string) *string {
+	name := fmt.Sprintf("Report_%s", time.Now().Format("2006-01-02"))
+	if suffix != nil {
+		name += "_" + *suffix
+	}
+	return AsPtr(name + "." + ext)
+}
+
+// Keys of the map in alphabetical order joined with separator
+func SortedKeys[M ~map[string]V, V any](m M, sep *string) *string {
+	keys := make(ListString, 0, len(m))
+	for k := range m {
+		keys = append(keys, AsPtr(k))
+	}
+	slices.SortFunc(keys, func(a, b *string) int { return strings.Compare(*a, *b) })
+	return StringsJoin(&keys, sep)
 }
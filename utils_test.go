@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWeekRange(t *testing.T) {
+	tests := []struct {
+		str     string
+		year    Year
+		month   Month
+		start   string
+		end     string
+		wantErr bool
+	}{
+		{str: "2.10.-8.10.", year: 2023, month: 10, start: "2023-10-02", end: "2023-10-08"},
+		{str: "16.-22.10.", year: 2023, month: 10, start: "2023-10-16", end: "2023-10-22"},
+		{str: "30.10.-5.11.", year: 2023, month: 10, start: "2023-10-30", end: "2023-11-05"},
+		{str: "30.10.-5.11.", year: 2023, month: 11, start: "2023-10-30", end: "2023-11-05"},
+		{str: "25.12.-31.12.", year: 2023, month: 12, start: "2023-12-25", end: "2023-12-31"},
+		{str: "30.12.-5.1.", year: 2024, month: 1, start: "2023-12-30", end: "2024-01-05"},
+		{str: "30.12.-5.1.", year: 2023, month: 12, start: "2023-12-30", end: "2024-01-05"},
+		{str: "31.11.-6.12.", year: 2023, month: 11, wantErr: true},
+		{str: "8.10.-2.10.", year: 2023, month: 10, wantErr: true},
+		{str: "week 40", year: 2023, month: 10, wantErr: true},
+		{str: "2.10.-8.10.", year: 0, month: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		start, end, err := ParseWeekRange(&tt.str, tt.year, tt.month)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s (%v-%v): expected error", tt.str, tt.year, tt.month)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s (%v-%v): unexpected error: %v", tt.str, tt.year, tt.month, err)
+			continue
+		}
+		if start.Format(time.DateOnly) != tt.start || end.Format(time.DateOnly) != tt.end {
+			t.Errorf("%s (%v-%v): got %s - %s, want %s - %s", tt.str, tt.year, tt.month, start.Format(time.DateOnly), end.Format(time.DateOnly), tt.start, tt.end)
+		}
+	}
+}